@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+// serialBaud is the baud rate used by rf95.OpenSerial.
+const serialBaud = 115200
+
+// modes to be benchmarked.
+var modes = []rf95.ModemMode{
+	rf95.MediumRange,
+	rf95.FastShortRange,
+	rf95.SlowLongRange,
+	rf95.SlowLongRange2,
+	rf95.SlowLongRange3,
+}
+
+// serialTime estimates the time to write an AT+TX command for n bytes over the serial link.
+//
+// Each byte is hex encoded and each character takes ten bits, including start and stop bit.
+func serialTime(n int) time.Duration {
+	chars := len("AT+TX=") + 2*n + 1
+	return time.Duration(chars*10) * time.Second / serialBaud
+}
+
+// throughput in bytes per second for n bytes within d.
+func throughput(n int, d time.Duration) float64 {
+	return float64(n) / d.Seconds()
+}
+
+// bench transmits packets of the MTU's size and returns the mean duration per packet.
+func bench(modem *rf95.Modem, mtu, packets int) (time.Duration, error) {
+	payload := make([]byte, mtu)
+
+	start := time.Now()
+	for i := 0; i < packets; i++ {
+		if _, err := modem.Transmit(payload); err != nil {
+			return 0, err
+		}
+	}
+
+	return time.Since(start) / time.Duration(packets), nil
+}
+
+func main() {
+	if len(os.Args) != 4 {
+		fmt.Printf("Usage:   %s DEVICE FREQ PACKETS\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0 868.5 10\n\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer sigintCtxCancel()
+
+	modem, modemErr := rf95.OpenSerial(os.Args[1], sigintCtx)
+	if modemErr != nil {
+		panic(modemErr)
+	}
+
+	if freq, freqErr := strconv.ParseFloat(os.Args[2], 64); freqErr != nil {
+		panic(freqErr)
+	} else if freqErr = modem.Frequency(freq); freqErr != nil {
+		panic(freqErr)
+	}
+
+	packets, packetsErr := strconv.Atoi(os.Args[3])
+	if packetsErr != nil {
+		panic(packetsErr)
+	} else if packets < 1 {
+		panic("at least one packet is required")
+	}
+
+	fmt.Println("mode,mtu,airtime_ms,serial_ms,measured_ms,theoretical_bps,measured_bps,efficiency,bottleneck")
+
+	for _, mode := range modes {
+		if modeErr := modem.Mode(mode); modeErr != nil {
+			panic(modeErr)
+		}
+
+		status, statusErr := modem.FetchStatus()
+		if statusErr != nil {
+			panic(statusErr)
+		}
+
+		measured, benchErr := bench(modem, status.Mtu, packets)
+		if benchErr != nil {
+			panic(benchErr)
+		}
+
+		airtime := mode.Airtime(status.Mtu)
+		serial := serialTime(status.Mtu)
+
+		// The overhead beyond the airtime is either explained by the serial link or
+		// by the scheduling between host and firmware.
+		bottleneck := "radio"
+		if overhead := measured - airtime; overhead > airtime/10 {
+			if serial >= overhead/2 {
+				bottleneck = "serial"
+			} else {
+				bottleneck = "scheduling"
+			}
+		}
+
+		fmt.Printf("%d,%d,%.1f,%.1f,%.1f,%.1f,%.1f,%.2f,%s\n",
+			mode, status.Mtu,
+			float64(airtime)/float64(time.Millisecond),
+			float64(serial)/float64(time.Millisecond),
+			float64(measured)/float64(time.Millisecond),
+			throughput(status.Mtu, airtime), throughput(status.Mtu, measured),
+			float64(airtime)/float64(measured),
+			bottleneck)
+	}
+
+	if closeErr := modem.Close(); closeErr != nil {
+		panic(closeErr)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+// bundle collects named files for the support bundle archive.
+type bundle struct {
+	buff bytes.Buffer
+	tw   *tar.Writer
+	gw   *gzip.Writer
+}
+
+// newBundle creates an empty bundle.
+func newBundle() *bundle {
+	b := &bundle{}
+	b.gw = gzip.NewWriter(&b.buff)
+	b.tw = tar.NewWriter(b.gw)
+	return b
+}
+
+// add a file of the given name and content.
+func (b *bundle) add(name string, content []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := b.tw.Write(content)
+	return err
+}
+
+// addJson adds the value as an indented JSON file.
+func (b *bundle) addJson(name string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.add(name, append(content, '\n'))
+}
+
+// addError records a failed collection step instead of its file.
+func (b *bundle) addError(name string, err error) error {
+	return b.add(name+".error", []byte(err.Error()+"\n"))
+}
+
+// finish the archive and return its bytes.
+func (b *bundle) finish() ([]byte, error) {
+	if err := b.tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := b.gw.Close(); err != nil {
+		return nil, err
+	}
+	return b.buff.Bytes(), nil
+}
+
+// environment describes the host for the support bundle.
+func environment(device string) map[string]string {
+	env := map[string]string{
+		"device":     device,
+		"time":       time.Now().Format(time.RFC3339),
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		env["hostname"] = hostname
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/dtn7/rf95modem-go" {
+				env["library_version"] = dep.Version
+			}
+		}
+		if info.Main.Path == "github.com/dtn7/rf95modem-go" {
+			env["library_version"] = info.Main.Version
+		}
+	}
+
+	return env
+}
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Printf("Usage:   %s DEVICE OUTPUT\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0 rf95-support.tar.gz\n\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer sigintCtxCancel()
+
+	b := newBundle()
+	if err := b.addJson("environment.json", environment(os.Args[1])); err != nil {
+		panic(err)
+	}
+
+	modem, modemErr := rf95.OpenSerial(os.Args[1], sigintCtx)
+	if modemErr != nil {
+		if err := b.addError("modem", modemErr); err != nil {
+			panic(err)
+		}
+	} else {
+		if status, statusErr := modem.FetchStatus(); statusErr != nil {
+			if err := b.addError("status.json", statusErr); err != nil {
+				panic(err)
+			}
+		} else if err := b.addJson("status.json", status); err != nil {
+			panic(err)
+		}
+
+		var state bytes.Buffer
+		if stateErr := modem.ExportState(&state); stateErr != nil {
+			if err := b.addError("state.json", stateErr); err != nil {
+				panic(err)
+			}
+		} else if err := b.add("state.json", state.Bytes()); err != nil {
+			panic(err)
+		}
+
+		if err := b.addJson("linkstats.json", modem.LinkStats()); err != nil {
+			panic(err)
+		}
+
+		if err := b.addJson("latencies.json", modem.Latencies()); err != nil {
+			panic(err)
+		}
+
+		var transcript bytes.Buffer
+		if err := modem.DumpTranscript(&transcript); err != nil {
+			panic(err)
+		} else if err := b.add("transcript.txt", transcript.Bytes()); err != nil {
+			panic(err)
+		}
+
+		if closeErr := modem.Close(); closeErr != nil {
+			panic(closeErr)
+		}
+	}
+
+	archive, archiveErr := b.finish()
+	if archiveErr != nil {
+		panic(archiveErr)
+	}
+
+	if err := os.WriteFile(os.Args[2], archive, 0644); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Wrote support bundle to %s\n", os.Args[2])
+}
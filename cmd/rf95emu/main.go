@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Printf("Usage:   %s ADDRESS|DEVICE\n", os.Args[0])
+		fmt.Printf("Example: %s :9095\n", os.Args[0])
+		fmt.Printf("         %s /dev/ttyUSB0\n\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer sigintCtxCancel()
+
+	emulator := rf95.NewEmulator()
+
+	// A network address contains a port, while a serial device does not.
+	if strings.Contains(os.Args[1], ":") {
+		listener, listenerErr := net.Listen("tcp", os.Args[1])
+		if listenerErr != nil {
+			panic(listenerErr)
+		}
+
+		go func() {
+			<-sigintCtx.Done()
+			_ = listener.Close()
+		}()
+
+		fmt.Printf("Emulating rf95modems on %s\n", listener.Addr())
+
+		if err := emulator.Serve(listener); err != nil && sigintCtx.Err() == nil {
+			panic(err)
+		}
+		return
+	}
+
+	port, portErr := rf95.SerialOpener(os.Args[1], rf95.DefaultSerialConfig)(sigintCtx)
+	if portErr != nil {
+		panic(portErr)
+	}
+
+	go func() {
+		<-sigintCtx.Done()
+		_ = port.Close()
+	}()
+
+	fmt.Printf("Emulating an rf95modem on %s\n", os.Args[1])
+
+	if err := emulator.ServeConn(port); err != nil && sigintCtx.Err() == nil {
+		panic(err)
+	}
+}
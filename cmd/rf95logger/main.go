@@ -1,50 +1,133 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/dtn7/rf95modem-go/internal/metrics"
 	"github.com/dtn7/rf95modem-go/rf95"
 )
 
-// handler prints the received message with its RSSI and SNR as a CSV on the stdout.
+// csvHeader names the fields of each line created by csvLine.
+const csvHeader = "unix_nanosec,payload,rssi,snr"
+
+// csvLine formats the received message with its RSSI and SNR as a CSV line.
+func csvLine(rx rf95.RxMessage) string {
+	return fmt.Sprintf("%d,%x,%d,%d\n", rx.Time.UnixNano(), rx.Payload, rx.Rssi, rx.Snr)
+}
+
+// handler prints the received message as a CSV line on the stdout.
 func handler(rx rf95.RxMessage) {
-	fmt.Printf("%d,%x,%d,%d\n", time.Now().UnixNano(), rx.Payload, rx.Rssi, rx.Snr)
+	fmt.Print(csvLine(rx))
+}
+
+// cborHandler writes the received message as a CBOR data item to the stdout,
+// resulting in a CBOR sequence as specified in RFC 8742.
+func cborHandler(rx rf95.RxMessage) {
+	item, _ := rx.MarshalCBOR()
+	_, _ = os.Stdout.Write(item)
+}
+
+const (
+	// triggerPre is the number of messages captured before a trigger.
+	triggerPre = 32
+
+	// triggerPost is the capture window after a trigger.
+	triggerPost = 10 * time.Second
+)
+
+// writeCapture stores a Trigger's capture as a CSV file, named after its first message.
+func writeCapture(capture []rf95.RxMessage) {
+	var content strings.Builder
+	content.WriteString(csvHeader + "\n")
+	for _, rx := range capture {
+		content.WriteString(csvLine(rx))
+	}
+
+	name := fmt.Sprintf("rf95capture-%d.csv", capture[0].Time.UnixNano())
+	if err := os.WriteFile(name, []byte(content.String()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Writing capture %s errored: %v\n", name, err)
+	}
 }
 
 func main() {
-	if len(os.Args) != 4 {
-		fmt.Printf("Usage:   %s DEVICE FREQ MODE-NO\n", os.Args[0])
-		fmt.Printf("Example: %s /dev/ttyUSB0 868.5 0\n\n", os.Args[0])
+	metricsAddr := metrics.Flag()
+	flag.Usage = func() {
+		fmt.Printf("Usage:   %s [--metrics-addr ADDRESS] DEVICE FREQ MODE-NO [TRIGGER-PREFIX]\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0 868.5 0\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0 868.5 0 cafe\n", os.Args[0])
+		fmt.Printf("Example: %s --metrics-addr :9100 /dev/ttyUSB0 868.5 0\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.CommandLine.SetOutput(os.Stdout)
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 3 && len(args) != 4 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
 	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer sigintCtxCancel()
 
-	modem, modemErr := rf95.OpenSerial(os.Args[1], sigintCtx)
+	modem, modemErr := rf95.OpenSerial(args[0], sigintCtx)
 	if modemErr != nil {
 		panic(modemErr)
 	}
 
-	if freq, freqErr := strconv.ParseFloat(os.Args[2], 64); freqErr != nil {
+	if metricsAddr, metricsErr := metrics.Serve(*metricsAddr, modem, sigintCtx); metricsErr != nil {
+		panic(metricsErr)
+	} else if metricsAddr != "" {
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s\n", metricsAddr)
+	}
+
+	if freq, freqErr := strconv.ParseFloat(args[1], 64); freqErr != nil {
 		panic(freqErr)
 	} else if freqErr = modem.Frequency(freq); freqErr != nil {
 		panic(freqErr)
 	}
 
-	if modeNo, modeNoErr := strconv.Atoi(os.Args[3]); modeNoErr != nil {
+	if modeNo, modeNoErr := strconv.Atoi(args[2]); modeNoErr != nil {
 		panic(modeNoErr)
 	} else if modeNoErr = modem.Mode(rf95.ModemMode(modeNo)); modeNoErr != nil {
 		panic(modeNoErr)
 	}
 
-	fmt.Println("unix_nanosec,payload,rssi,snr")
-	if _, regErr := modem.RegisterHandlers(handler, nil); regErr != nil {
+	if len(args) == 4 {
+		prefix, prefixErr := hex.DecodeString(args[3])
+		if prefixErr != nil {
+			panic(prefixErr)
+		}
+
+		trigger, triggerErr := rf95.NewTrigger(func(rx rf95.RxMessage) bool {
+			return bytes.HasPrefix(rx.Payload, prefix)
+		}, triggerPre, triggerPost, writeCapture)
+		if triggerErr != nil {
+			panic(triggerErr)
+		}
+		if _, regErr := modem.RegisterHandlers(trigger.HandleRx, nil); regErr != nil {
+			panic(regErr)
+		}
+	}
+
+	// Constrained consumers might request a CBOR sequence instead of CSV.
+	rxHandler := handler
+	if os.Getenv("RF95LOGGER_FORMAT") == "cbor" {
+		rxHandler = cborHandler
+	} else {
+		fmt.Println(csvHeader)
+	}
+
+	if _, regErr := modem.RegisterHandlers(rxHandler, nil); regErr != nil {
 		panic(regErr)
 	}
 
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtn7/rf95modem-go/discovery"
+	"github.com/dtn7/rf95modem-go/internal/metrics"
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+// instance names the proxied device for DNS-SD, e.g., "raspberrypi ttyUSB0".
+func instance(device string) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "rf95proxy"
+	}
+	return hostname + " " + filepath.Base(device)
+}
+
+// parseChaos reads a Chaos from comma separated settings, e.g., "latency=200ms,drop=0.1,disconnect=0.01".
+func parseChaos(settings string) (chaos *rf95.Chaos, err error) {
+	chaos = &rf95.Chaos{}
+	for _, setting := range strings.Split(settings, ",") {
+		key, value, _ := strings.Cut(setting, "=")
+		switch key {
+		case "latency":
+			chaos.Latency, err = time.ParseDuration(value)
+		case "drop":
+			chaos.DropRate, err = strconv.ParseFloat(value, 64)
+		case "disconnect":
+			chaos.DisconnectRate, err = strconv.ParseFloat(value, 64)
+		default:
+			err = fmt.Errorf("unknown chaos setting %q", setting)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func main() {
+	metricsAddr := metrics.Flag()
+	flag.Usage = func() {
+		fmt.Printf("Usage:   %s [--metrics-addr ADDRESS] DEVICE ADDRESS\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0 :9095\n", os.Args[0])
+		fmt.Printf("Example: %s --metrics-addr :9100 /dev/ttyUSB0 :9095\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.CommandLine.SetOutput(os.Stdout)
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer sigintCtxCancel()
+
+	modem, modemErr := rf95.OpenSerial(args[0], sigintCtx)
+	if modemErr != nil {
+		panic(modemErr)
+	}
+
+	if metricsAddr, metricsErr := metrics.Serve(*metricsAddr, modem, sigintCtx); metricsErr != nil {
+		panic(metricsErr)
+	} else if metricsAddr != "" {
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s\n", metricsAddr)
+	}
+
+	listener, listenerErr := net.Listen("tcp", args[1])
+	if listenerErr != nil {
+		panic(listenerErr)
+	}
+
+	fmt.Printf("Serving %s on %s\n", args[0], listener.Addr())
+
+	// Advertise the proxy on the LAN, allowing clients to find it by discovery.Browse or Lookup.
+	if status, statusErr := modem.FetchStatus(); statusErr != nil {
+		panic(statusErr)
+	} else if advErr := discovery.Advertise(instance(args[0]), listener.Addr().(*net.TCPAddr).Port, status, sigintCtx); advErr != nil {
+		fmt.Printf("Advertising errored: %v\n", advErr)
+	}
+
+	server, serverErr := rf95.NewServer(modem)
+	if serverErr != nil {
+		panic(serverErr)
+	}
+
+	// Developers might degrade the proxy on purpose to test their applications.
+	if settings := os.Getenv("RF95PROXY_CHAOS"); settings != "" {
+		chaos, chaosErr := parseChaos(settings)
+		if chaosErr != nil {
+			panic(chaosErr)
+		}
+		server.Chaos = func(net.Addr) *rf95.Chaos { return chaos }
+
+		fmt.Printf("Degrading all clients by %+v\n", *chaos)
+	}
+
+	if err := server.Serve(listener); err != nil && sigintCtx.Err() == nil {
+		panic(err)
+	}
+
+	if err := modem.Close(); err != nil {
+		fmt.Printf("Closing errored: %v\n", err)
+	}
+}
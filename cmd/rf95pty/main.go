@@ -2,28 +2,46 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 
+	"github.com/dtn7/rf95modem-go/internal/metrics"
 	"github.com/dtn7/rf95modem-go/rf95"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Printf("Usage:   %s DEVICE\n", os.Args[0])
-		fmt.Printf("Example: %s /dev/ttyUSB0\n\n", os.Args[0])
+	metricsAddr := metrics.Flag()
+	flag.Usage = func() {
+		fmt.Printf("Usage:   %s [--metrics-addr ADDRESS] DEVICE\n", os.Args[0])
+		fmt.Printf("Example: %s /dev/ttyUSB0\n", os.Args[0])
+		fmt.Printf("Example: %s --metrics-addr :9100 /dev/ttyUSB0\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.CommandLine.SetOutput(os.Stdout)
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
 	sigintCtx, sigintCtxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer sigintCtxCancel()
 
-	modem, modemErr := rf95.OpenSerial(os.Args[1], sigintCtx)
+	modem, modemErr := rf95.OpenSerial(args[0], sigintCtx)
 	if modemErr != nil {
 		panic(modemErr)
 	}
 
+	if metricsAddr, metricsErr := metrics.Serve(*metricsAddr, modem, sigintCtx); metricsErr != nil {
+		panic(metricsErr)
+	} else if metricsAddr != "" {
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s\n", metricsAddr)
+	}
+
 	if status, statusErr := modem.FetchStatus(); statusErr != nil {
 		panic(statusErr)
 	} else {
@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+const (
+	// AnnouncePort is the default UDP port of rf95modem gateways' broadcast announcements.
+	AnnouncePort = 2343
+
+	// announceMagic starts each announcement, followed by newline separated
+	// records, e.g., "instance=gateway", "port=2342", and those of statusTxt.
+	announceMagic = "rf95modem"
+)
+
+// announcement encodes an rf95modem, reachable at the port, into a datagram.
+func announcement(instance string, port int, status rf95.Status) []byte {
+	records := append([]string{announceMagic, "instance=" + instance, "port=" + strconv.Itoa(port)}, statusTxt(status)...)
+	return []byte(strings.Join(records, "\n"))
+}
+
+// parseAnnouncement decodes a datagram, sent from addr, into an Endpoint.
+//
+// Datagrams without the magic or a valid port are rejected. The Endpoint's
+// host is the sender's address.
+func parseAnnouncement(datagram []byte, addr *net.UDPAddr) (endpoint Endpoint, ok bool) {
+	records := strings.Split(string(bytes.TrimSpace(datagram)), "\n")
+	if records[0] != announceMagic {
+		return
+	}
+
+	for _, record := range records[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(record), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "instance":
+			endpoint.Instance = value
+		case "port":
+			if port, err := strconv.Atoi(value); err == nil && port > 0 && port <= 0xffff {
+				endpoint.Port = port
+			}
+		}
+	}
+	if endpoint.Port == 0 {
+		return
+	}
+
+	endpoint.parseTxt(records[1:])
+	endpoint.Host = addr.IP.String()
+	endpoint.Addrs = []net.IP{addr.IP}
+	ok = true
+	return
+}
+
+// ListenAnnouncements collects rf95modem gateways' UDP broadcast announcements for the given window.
+//
+// The address to listen on is a host and port, e.g., ":2343" for the
+// AnnouncePort on all interfaces. Each gateway is reported once, with its
+// latest announcement. Its Address can be passed to a network constructor like
+// rf95.OpenTLS. Collecting stops early when the Context is done.
+func ListenAnnouncements(address string, window time.Duration, ctx context.Context) ([]Endpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	var listenConfig net.ListenConfig
+	packetConn, err := listenConfig.ListenPacket(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn := packetConn.(*net.UDPConn)
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	var endpoints []Endpoint
+	known := make(map[string]int)
+
+	buff := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFromUDP(buff)
+		if err != nil {
+			if ctx.Err() != nil {
+				return endpoints, nil
+			}
+			return endpoints, err
+		}
+
+		endpoint, ok := parseAnnouncement(buff[:n], addr)
+		if !ok {
+			continue
+		}
+
+		if i, ok := known[endpoint.Address()]; ok {
+			endpoints[i] = endpoint
+		} else {
+			known[endpoint.Address()] = len(endpoints)
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+}
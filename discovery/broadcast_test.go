@@ -0,0 +1,64 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+func TestParseAnnouncement(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}
+	status := rf95.Status{Firmware: "0.7.3", Mode: rf95.FastShortRange, Mtu: 251, Frequency: 868.1}
+
+	endpoint, ok := parseAnnouncement(announcement("gateway", 2342, status), addr)
+	if !ok {
+		t.Fatalf("announcement was rejected")
+	}
+	if endpoint.Instance != "gateway" || endpoint.Address() != "10.0.0.1:2342" || endpoint.Mtu != 251 {
+		t.Fatalf("parsed endpoint %#v does not match the announcement", endpoint)
+	}
+
+	for _, datagram := range []string{"", "garbage\nport=2342", "rf95modem\ninstance=gateway", "rf95modem\nport=NaN", "rf95modem\nport=70000"} {
+		if _, ok := parseAnnouncement([]byte(datagram), addr); ok {
+			t.Fatalf("datagram %q was accepted", datagram)
+		}
+	}
+}
+
+func TestListenAnnouncements(t *testing.T) {
+	// Reserve a free port, released again for ListenAnnouncements.
+	reserved, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := reserved.LocalAddr().String()
+	_ = reserved.Close()
+
+	go func() {
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for i := 0; i < 10; i++ {
+			time.Sleep(10 * time.Millisecond)
+			_, _ = conn.Write(announcement("gateway", 2342, rf95.Status{Mtu: 251}))
+			_, _ = conn.Write([]byte("garbage"))
+		}
+	}()
+
+	endpoints, err := ListenAnnouncements(address, 300*time.Millisecond, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("collected %d endpoints, expected 1", len(endpoints))
+	}
+	if endpoints[0].Address() != "127.0.0.1:2342" {
+		t.Fatalf("endpoint's address is %s, expected 127.0.0.1:2342", endpoints[0].Address())
+	}
+}
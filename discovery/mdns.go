@@ -0,0 +1,180 @@
+// Package discovery advertises and finds networked rf95modems via mDNS/DNS-SD or UDP broadcasts.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+	"github.com/grandcat/zeroconf"
+)
+
+const (
+	// Service is the DNS-SD service type of networked rf95modems.
+	Service = "_rf95modem._tcp"
+
+	// domain to advertise and browse within.
+	domain = "local."
+)
+
+// Endpoint is a networked rf95modem found by Browse.
+type Endpoint struct {
+	// Instance is the advertised name of this rf95modem.
+	Instance string
+
+	// Host and Port to connect to.
+	Host  string
+	Port  int
+	Addrs []net.IP
+
+	// Firmware, Mode, Frequency and Mtu as advertised; zero if unknown.
+	Firmware  string
+	Mode      rf95.ModemMode
+	Frequency float64
+	Mtu       int
+}
+
+// Address of the Endpoint as a host and port, to be passed to a network constructor like rf95.OpenTLS.
+func (endpoint Endpoint) Address() string {
+	host := strings.TrimSuffix(endpoint.Host, ".")
+	if len(endpoint.Addrs) > 0 {
+		host = endpoint.Addrs[0].String()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(endpoint.Port))
+}
+
+// statusTxt encodes the rf95modem's Status into TXT records.
+func statusTxt(status rf95.Status) []string {
+	return []string{
+		"firmware=" + status.Firmware,
+		fmt.Sprintf("mode=%d", status.Mode),
+		fmt.Sprintf("frequency=%.2f", status.Frequency),
+		fmt.Sprintf("mtu=%d", status.Mtu),
+	}
+}
+
+// parseTxt decodes TXT records, created by statusTxt, into the Endpoint.
+//
+// Unknown or malformed records are ignored.
+func (endpoint *Endpoint) parseTxt(txt []string) {
+	for _, record := range txt {
+		key, value, ok := strings.Cut(record, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "firmware":
+			endpoint.Firmware = value
+		case "mode":
+			if mode, err := strconv.Atoi(value); err == nil {
+				endpoint.Mode = rf95.ModemMode(mode)
+			}
+		case "frequency":
+			if freq, err := strconv.ParseFloat(value, 64); err == nil {
+				endpoint.Frequency = freq
+			}
+		case "mtu":
+			if mtu, err := strconv.Atoi(value); err == nil {
+				endpoint.Mtu = mtu
+			}
+		}
+	}
+}
+
+// Advertise an exported rf95modem, reachable at the port, under the instance name.
+//
+// The Status is included as TXT records, allowing clients to pick a modem
+// before connecting. The advertisement runs in the background until the
+// Context is done.
+func Advertise(instance string, port int, status rf95.Status, ctx context.Context) error {
+	server, err := zeroconf.Register(instance, Service, domain, port, statusTxt(status), nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown()
+	}()
+
+	return nil
+}
+
+// Browse for networked rf95modems until the Context is done, e.g., by a timeout.
+func Browse(ctx context.Context) ([]Endpoint, error) {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	if err := resolver.Browse(ctx, Service, domain, entries); err != nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+	for {
+		select {
+		case <-ctx.Done():
+			return endpoints, nil
+
+		case entry, ok := <-entries:
+			if !ok {
+				return endpoints, nil
+			}
+
+			endpoints = append(endpoints, entryEndpoint(entry))
+		}
+	}
+}
+
+// Lookup the networked rf95modem advertised under the instance name, e.g., by rf95proxy.
+//
+// This waits until the rf95modem was found or the Context is done, e.g., by a
+// timeout, which results in an error.
+func Lookup(instance string, ctx context.Context) (endpoint Endpoint, err error) {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return
+	}
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	if err = resolver.Lookup(ctx, instance, Service, domain, entries); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("rf95modem %s was not found: %v", instance, ctx.Err())
+			return
+
+		case entry, ok := <-entries:
+			if !ok {
+				err = fmt.Errorf("rf95modem %s was not found", instance)
+				return
+			} else if entry.Instance != instance || entry.Port == 0 {
+				continue
+			}
+
+			endpoint = entryEndpoint(entry)
+			return
+		}
+	}
+}
+
+// entryEndpoint converts a resolved DNS-SD entry into an Endpoint.
+func entryEndpoint(entry *zeroconf.ServiceEntry) Endpoint {
+	endpoint := Endpoint{
+		Instance: entry.Instance,
+		Host:     entry.HostName,
+		Port:     entry.Port,
+		Addrs:    append(entry.AddrIPv4, entry.AddrIPv6...),
+	}
+	endpoint.parseTxt(entry.Text)
+	return endpoint
+}
@@ -0,0 +1,42 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+func TestStatusTxt(t *testing.T) {
+	status := rf95.Status{
+		Firmware:  "0.7.3",
+		Mode:      rf95.FastShortRange,
+		Mtu:       251,
+		Frequency: 868.1,
+	}
+
+	var endpoint Endpoint
+	endpoint.parseTxt(append(statusTxt(status), "garbage", "mtu=NaN"))
+
+	if endpoint.Firmware != status.Firmware || endpoint.Mode != status.Mode ||
+		endpoint.Frequency != status.Frequency || endpoint.Mtu != status.Mtu {
+		t.Fatalf("parsed endpoint %#v does not match status %#v", endpoint, status)
+	}
+}
+
+func TestEndpointAddress(t *testing.T) {
+	tests := []struct {
+		endpoint Endpoint
+		address  string
+	}{
+		{Endpoint{Host: "gateway.local.", Port: 2342}, "gateway.local:2342"},
+		{Endpoint{Host: "gateway.local.", Port: 2342, Addrs: []net.IP{net.IPv4(10, 0, 0, 1)}}, "10.0.0.1:2342"},
+		{Endpoint{Host: "gateway.local.", Port: 2342, Addrs: []net.IP{net.ParseIP("fe80::1")}}, "[fe80::1]:2342"},
+	}
+
+	for _, test := range tests {
+		if address := test.endpoint.Address(); address != test.address {
+			t.Fatalf("address is %s, expected %s", address, test.address)
+		}
+	}
+}
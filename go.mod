@@ -2,6 +2,19 @@ module github.com/dtn7/rf95modem-go
 
 go 1.20
 
-require github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07
+require (
+	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/grandcat/zeroconf v1.0.0
+	go.bug.st/serial v1.6.4
+	golang.org/x/crypto v0.26.0
+)
 
-require golang.org/x/sys v0.9.0 // indirect
+require (
+	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
+	github.com/creack/goselect v0.1.2 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/miekg/dns v1.1.27 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sync v0.7.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
+)
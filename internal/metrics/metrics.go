@@ -0,0 +1,61 @@
+// Package metrics serves a Modem's metrics and pprof profiles for the rf95 commands.
+package metrics
+
+import (
+	"context"
+	"flag"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+
+	"github.com/dtn7/rf95modem-go/rf95"
+)
+
+// AddressEnv names the environment variable of the endpoint's listen address,
+// e.g., :9100, the default of the --metrics-addr flag.
+const AddressEnv = "RF95_METRICS_ADDR"
+
+// Flag registers the --metrics-addr flag of the endpoint's listen address, to be passed to Serve after flag.Parse.
+func Flag() *string {
+	return flag.String("metrics-addr", os.Getenv(AddressEnv),
+		"serve metrics, the transcript, and pprof on this address, e.g., :9100; falls back to $"+AddressEnv)
+}
+
+// Serve the Modem's Prometheus metrics at /metrics, its raw transcript at
+// /debug/transcript, and pprof at /debug/pprof/ until the Context is done.
+//
+// Nothing is served for an empty listen address, e.g., of an unset Flag. The
+// returned address is the endpoint's, empty if nothing is served.
+func Serve(listenAddress string, modem *rf95.Modem, ctx context.Context) (address string, err error) {
+	if listenAddress == "" {
+		return
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", modem.MetricsHandler())
+	mux.HandleFunc("/debug/transcript", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = modem.DumpTranscript(w)
+	})
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go func() { _ = server.Serve(listener) }()
+
+	address = listener.Addr().String()
+	return
+}
@@ -0,0 +1,53 @@
+package rf95
+
+import (
+	"math"
+	"time"
+)
+
+// modemModeParams are the LoRa PHY parameters behind a ModemMode.
+type modemModeParams struct {
+	bandwidth    float64 // in Hz
+	spreading    int     // spreading factor, log2 of chips/symbol
+	codingRate   int     // denominator of 4/x
+	lowDataRate  bool    // low data rate optimization
+	preambleSyms int
+}
+
+// modemModes maps each known ModemMode to its parameters, as configured by RadioHead's RH_RF95.
+var modemModes = map[ModemMode]modemModeParams{
+	MediumRange:    {125000, 7, 5, false, 8},
+	FastShortRange: {500000, 7, 5, false, 8},
+	SlowLongRange:  {31250, 9, 8, false, 8},
+	SlowLongRange2: {125000, 12, 8, true, 8},
+	SlowLongRange3: {125000, 11, 5, false, 8},
+}
+
+// radioHeadHeaderLen is the length of RadioHead's header, prepended to each payload.
+const radioHeadHeaderLen = 4
+
+// Airtime calculates the time on air of a payload of the given length.
+//
+// The calculation follows the SX1276 datasheet for explicit header mode with
+// enabled CRC, including the header added by the rf95modem's RadioHead stack.
+// An unknown ModemMode results in zero.
+func (mode ModemMode) Airtime(payloadLen int) time.Duration {
+	params, ok := modemModes[mode]
+	if !ok {
+		return 0
+	}
+
+	symbol := math.Exp2(float64(params.spreading)) / params.bandwidth
+	preamble := (float64(params.preambleSyms) + 4.25) * symbol
+
+	de := 0
+	if params.lowDataRate {
+		de = 1
+	}
+
+	payloadBits := 8*(payloadLen+radioHeadHeaderLen) - 4*params.spreading + 28 + 16
+	payloadSyms := 8 + math.Max(
+		math.Ceil(float64(payloadBits)/float64(4*(params.spreading-2*de)))*float64(params.codingRate), 0)
+
+	return time.Duration((preamble + payloadSyms*symbol) * float64(time.Second))
+}
@@ -0,0 +1,27 @@
+package rf95
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModemModeAirtime(t *testing.T) {
+	tests := []struct {
+		mode       ModemMode
+		payloadLen int
+		airtime    time.Duration
+	}{
+		{MediumRange, 10, 46336 * time.Microsecond},
+		{FastShortRange, 0, 7744 * time.Microsecond},
+		{SlowLongRange2, 251, 14032896 * time.Microsecond},
+		{ModemMode(23), 10, 0},
+	}
+
+	for _, test := range tests {
+		airtime := test.mode.Airtime(test.payloadLen)
+		if diff := airtime - test.airtime; diff < -time.Microsecond || diff > time.Microsecond {
+			t.Fatalf("airtime of mode %d for %d bytes is %v, expected %v",
+				test.mode, test.payloadLen, airtime, test.airtime)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package rf95
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// backgroundWorker executes the functions of the bgQueue one after another and
+// runs within a Goroutine after OpenModem.
+//
+// A function is only started when no AT command is pending. Thus, background
+// tasks delay an application's command by at most the one in progress.
+func (modem *Modem) backgroundWorker() {
+	for {
+		select {
+		case <-modem.ctx.Done():
+			return
+
+		case fn := <-modem.bgQueue:
+			for atomic.LoadInt32(&modem.cmdPending) > 0 {
+				select {
+				case <-modem.ctx.Done():
+					return
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+
+			fn()
+		}
+	}
+}
+
+// background enqueues fn for the backgroundWorker. If this is not possible,
+// fail is called with the reason instead.
+func (modem *Modem) background(fn func(), fail func(error)) {
+	select {
+	case <-modem.ctx.Done():
+		fail(io.EOF)
+
+	case modem.bgQueue <- fn:
+
+	default:
+		fail(fmt.Errorf("background queue is full"))
+	}
+}
+
+// FetchStatusAsync queries the Status in the background and passes it to the callback.
+//
+// This is intended for periodic housekeeping which should not delay other AT
+// commands. Thus, the query waits until no other command is pending.
+func (modem *Modem) FetchStatusAsync(callback func(Status, error)) {
+	modem.background(
+		func() { callback(modem.FetchStatus()) },
+		func(err error) { callback(Status{}, err) })
+}
+
+// TransmitAsync transmits the byte array in the background and passes the result to the callback.
+//
+// Like FetchStatusAsync, the transmission waits until no other command is pending.
+func (modem *Modem) TransmitAsync(p []byte, callback func(int, error)) {
+	modem.background(
+		func() { callback(modem.Transmit(p)) },
+		func(err error) { callback(0, err) })
+}
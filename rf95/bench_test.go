@@ -0,0 +1,111 @@
+package rf95
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchRate paces the packets injected by the benchmarks into the testDevice, e.g.,
+// go test -bench . ./rf95 -args -rf95.rate=50 for fifty packets per second.
+var benchRate = flag.Float64("rf95.rate", 0, "packets per second injected by benchmarks, 0 for no pacing")
+
+// benchPacer returns a function blocking until the next packet is due.
+func benchPacer() func() {
+	if *benchRate <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *benchRate))
+	return func() { <-ticker.C }
+}
+
+// benchRxLine is an RX line of a packet of the MTU's size.
+var benchRxLine = fmt.Sprintf("+RX 251,%s,-15,8\n", strings.Repeat("41", 251))
+
+// newBenchModem creates a Modem backed by a testDevice, acknowledging each AT+TX.
+func newBenchModem(b *testing.B) (*Modem, *testDevice) {
+	return newTestModem(b, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+TX=") {
+			return []string{fmt.Sprintf("+SENT %d bytes.\n", len(cmd[len("AT+TX="):])/2)}
+		}
+		return nil
+	})
+}
+
+func BenchmarkParsePacketRx(b *testing.B) {
+	b.SetBytes(251)
+	for i := 0; i < b.N; i++ {
+		if _, err := parsePacketRx(benchRxLine); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	modem, dev := newBenchModem(b)
+
+	received := make(chan struct{}, 1)
+	if _, err := modem.RegisterHandlers(func(RxMessage) { received <- struct{}{} }, nil); err != nil {
+		b.Fatal(err)
+	}
+
+	pace := benchPacer()
+	b.SetBytes(251)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pace()
+		dev.inject(benchRxLine)
+		<-received
+	}
+}
+
+func BenchmarkStreamWrite(b *testing.B) {
+	modem, _ := newBenchModem(b)
+
+	stream, err := NewStream(modem)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Four packets per write, the last one only partially filled.
+	payload := make([]byte, 3*251+100)
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := stream.Write(payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamRead(b *testing.B) {
+	modem, dev := newBenchModem(b)
+
+	stream, err := NewStream(modem)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	pace := benchPacer()
+	buff := make([]byte, 251)
+	b.SetBytes(251)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pace()
+		dev.inject(benchRxLine)
+
+		for n := 0; n < len(buff); {
+			m, err := stream.Read(buff[n:])
+			if err != nil {
+				b.Fatal(err)
+			}
+			n += m
+		}
+	}
+}
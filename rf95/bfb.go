@@ -0,0 +1,43 @@
+package rf95
+
+import "fmt"
+
+// Bfb reports whether big BLE frames are enabled, as the Status' Bfb.
+func (modem *Modem) Bfb() (bool, error) {
+	status, err := modem.FetchStatus()
+	if err != nil {
+		return false, err
+	}
+	return status.Bfb != 0, nil
+}
+
+// SetBfb switches big BLE frames on or off.
+//
+// The firmware must support AT+BFB, as checked by Supports. As the setting
+// changes the rf95modem's maximum packet size, the MTU is refreshed afterwards
+// and passed to the MTU handlers. Thus, Streams adapt their fragmentation.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) SetBfb(enabled bool) error {
+	return modem.withLease(nil, func() error { return modem.setBfb(enabled) })
+}
+
+// setBfb implements SetBfb without respecting a ConfigLease.
+func (modem *Modem) setBfb(enabled bool) error {
+	if supported, err := modem.Supports("AT+BFB"); err != nil {
+		return err
+	} else if !supported {
+		return fmt.Errorf("firmware does not support AT+BFB")
+	}
+
+	cmd := "AT+BFB=0"
+	if enabled {
+		cmd = "AT+BFB=1"
+	}
+
+	if _, err := modem.execute(cmd); err != nil {
+		return err
+	}
+
+	return modem.refreshMtu()
+}
@@ -0,0 +1,52 @@
+package rf95
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModemSetBfb(t *testing.T) {
+	bfb := false
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		switch cmd {
+		case "AT+HELP":
+			help := append([]string{}, testHelp[:len(testHelp)-1]...)
+			return append(help, "AT+BFB=<0|1>        Big BLE frames on (1) or off (0).\n", "+OK\n")
+		case "AT+BFB=0", "AT+BFB=1":
+			bfb = cmd == "AT+BFB=1"
+			return []string{"+OK\n"}
+		case "AT+INFO":
+			info := append([]string{}, testInfo...)
+			if !bfb {
+				info[5], info[7] = "max pkt size:  20\n", "BFB:           0\n"
+			} else {
+				info[5], info[7] = "max pkt size:  251\n", "BFB:           1\n"
+			}
+			return info
+		default:
+			return nil
+		}
+	})
+
+	var mtus []int
+	if _, err := modem.RegisterHandlers(nil, func(mtu int) { mtus = append(mtus, mtu) }); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, enabled := range []bool{true, false} {
+		if err := modem.SetBfb(enabled); err != nil {
+			t.Fatal(err)
+		}
+
+		if bfb, err := modem.Bfb(); err != nil {
+			t.Fatal(err)
+		} else if bfb != enabled {
+			t.Fatalf("big BLE frames are %t, expected %t", bfb, enabled)
+		}
+	}
+
+	// The first MTU is reported on registration, before any change.
+	if expected := []int{20, 251, 20}; !reflect.DeepEqual(mtus, expected) {
+		t.Fatalf("MTU handler received %v, expected %v", mtus, expected)
+	}
+}
@@ -0,0 +1,29 @@
+package rf95
+
+import (
+	"fmt"
+)
+
+// Layer is implemented by protocol layers on top of a Modem which add a fixed
+// number of bytes to each packet, e.g., Signing.
+type Layer interface {
+	// Overhead in bytes added to each packet.
+	Overhead() int
+}
+
+// PayloadBudget calculates the usable payload per packet of the given MTU for stacked layers.
+//
+// An error is returned if the layers' combined overhead leaves no room for a
+// payload. Thus, such configurations can be refused upfront instead of failing
+// on each transmission.
+func PayloadBudget(mtu int, layers ...Layer) (int, error) {
+	budget := mtu
+	for _, layer := range layers {
+		budget -= layer.Overhead()
+	}
+
+	if budget <= 0 {
+		return 0, fmt.Errorf("headers of %d bytes exceed the MTU of %d bytes", mtu-budget, mtu)
+	}
+	return budget, nil
+}
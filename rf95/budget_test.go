@@ -0,0 +1,34 @@
+package rf95
+
+import (
+	"testing"
+)
+
+// testLayer is a Layer of a fixed overhead.
+type testLayer int
+
+func (layer testLayer) Overhead() int {
+	return int(layer)
+}
+
+func TestPayloadBudget(t *testing.T) {
+	tests := []struct {
+		mtu    int
+		layers []Layer
+		errors bool
+		budget int
+	}{
+		{251, nil, false, 251},
+		{251, []Layer{testLayer(4), &Signing{}}, false, 251 - 4 - SigningOverhead},
+		{64, []Layer{&Signing{}}, true, 0},
+		{72, []Layer{&Signing{}}, true, 0},
+	}
+
+	for _, test := range tests {
+		if budget, err := PayloadBudget(test.mtu, test.layers...); (err != nil) != test.errors {
+			t.Fatalf("budget for MTU %d returned error %v, expected %t", test.mtu, err, test.errors)
+		} else if budget != test.budget {
+			t.Fatalf("budget for MTU %d is %d, expected %d", test.mtu, budget, test.budget)
+		}
+	}
+}
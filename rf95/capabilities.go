@@ -0,0 +1,141 @@
+package rf95
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version of the rf95modem's firmware, e.g., 0.7.3.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+
+	// Suffix following the version numbers, e.g., "-dev" of 0.8.0-dev.
+	Suffix string
+}
+
+// ParseVersion of a firmware string as reported by AT+INFO, e.g., "0.7.3".
+//
+// Missing minor or patch numbers are treated as zero.
+func ParseVersion(firmware string) (version Version, err error) {
+	firmware = strings.TrimPrefix(strings.TrimSpace(firmware), "v")
+
+	numbers := firmware
+	if i := strings.IndexFunc(firmware, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		numbers, version.Suffix = firmware[:i], firmware[i:]
+	}
+
+	fields := strings.Split(numbers, ".")
+	if len(fields) > 3 || fields[0] == "" {
+		err = fmt.Errorf("firmware %q is no version", firmware)
+		return
+	}
+
+	parts := []*int{&version.Major, &version.Minor, &version.Patch}
+	for i, field := range fields {
+		if *parts[i], err = strconv.Atoi(field); err != nil {
+			err = fmt.Errorf("firmware %q is no version: %v", firmware, err)
+			return
+		}
+	}
+	return
+}
+
+// Compare two Versions by their numbers, returning -1, 0, or +1; the Suffix is ignored.
+func (version Version) Compare(other Version) int {
+	a := []int{version.Major, version.Minor, version.Patch}
+	b := []int{other.Major, other.Minor, other.Patch}
+
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (version Version) String() string {
+	return fmt.Sprintf("%d.%d.%d%s", version.Major, version.Minor, version.Patch, version.Suffix)
+}
+
+// FirmwareVersion parses the Status' Firmware, see ParseVersion.
+func (status Status) FirmwareVersion() (Version, error) {
+	return ParseVersion(status.Firmware)
+}
+
+// Capabilities of an rf95modem as a bitset, acquired by Modem.Capabilities.
+type Capabilities uint
+
+const (
+	// CapabilityGps is an onboard GPS receiver.
+	CapabilityGps Capabilities = 1 << iota
+
+	// CapabilityBle is a Bluetooth Low Energy interface.
+	CapabilityBle
+
+	// CapabilityBfb allows big BLE frames, see Modem.SetBfb.
+	CapabilityBfb
+
+	// CapabilityTxPower allows setting the transmit power, see Modem.TxPower.
+	CapabilityTxPower
+
+	// CapabilityReceive allows switching the RX listener, see Modem.SetReceive.
+	CapabilityReceive
+)
+
+// capabilityNames are the names of each Capabilities bit in order.
+var capabilityNames = []string{"GPS", "BLE", "BFB", "TXP", "RX"}
+
+// Has checks if all Capabilities of c are present.
+func (capabilities Capabilities) Has(c Capabilities) bool {
+	return capabilities&c == c
+}
+
+func (capabilities Capabilities) String() string {
+	var names []string
+	for i, name := range capabilityNames {
+		if capabilities.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Capabilities of the rf95modem, derived from its Status' Features and the
+// AT commands advertised by AT+HELP.
+//
+// Callers should feature-detect by this instead of issuing commands which fail
+// on older firmware builds.
+func (modem *Modem) Capabilities() (capabilities Capabilities, err error) {
+	status, err := modem.FetchStatus()
+	if err != nil {
+		return
+	}
+
+	if status.HasFeature(FeatureGps) {
+		capabilities |= CapabilityGps
+	}
+	if status.HasFeature(FeatureBle) {
+		capabilities |= CapabilityBle
+	}
+
+	for cmd, capability := range map[string]Capabilities{
+		"AT+BFB": CapabilityBfb,
+		"AT+TXP": CapabilityTxPower,
+		"AT+RX":  CapabilityReceive,
+	} {
+		supported, supportsErr := modem.Supports(cmd)
+		if supportsErr != nil {
+			err = supportsErr
+			return
+		} else if supported {
+			capabilities |= capability
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package rf95
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		firmware string
+		errors   bool
+		version  Version
+	}{
+		{"0.7.3", false, Version{0, 7, 3, ""}},
+		{"v1.2", false, Version{1, 2, 0, ""}},
+		{"0.8.0-dev", false, Version{0, 8, 0, "-dev"}},
+		{"2", false, Version{2, 0, 0, ""}},
+		{"", true, Version{}},
+		{"dev", true, Version{}},
+		{"1.2.3.4", true, Version{}},
+		{"1..2", true, Version{}},
+	}
+
+	for _, test := range tests {
+		if version, err := ParseVersion(test.firmware); (err != nil) != test.errors {
+			t.Fatalf("firmware %q returned error %v, expected %t", test.firmware, err, test.errors)
+		} else if !test.errors && version != test.version {
+			t.Fatalf("firmware %q is version %v, expected %v", test.firmware, version, test.version)
+		}
+	}
+
+	if (Version{0, 7, 3, ""}).Compare(Version{0, 10, 0, ""}) != -1 {
+		t.Fatalf("version 0.7.3 is not older than 0.10.0")
+	}
+}
+
+func TestModemCapabilities(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if cmd == "AT+HELP" {
+			return testHelp
+		}
+		return nil
+	})
+
+	capabilities, err := modem.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// testInfo reports LORA, GPS and BLE; testHelp advertises AT+RX.
+	if expected := CapabilityGps | CapabilityBle | CapabilityReceive; capabilities != expected {
+		t.Fatalf("capabilities are %v, expected %v", capabilities, expected)
+	}
+	if capabilities.Has(CapabilityGps|CapabilityBfb) || !capabilities.Has(CapabilityGps|CapabilityBle) {
+		t.Fatalf("capabilities %v are checked wrongly", capabilities)
+	}
+	if s := capabilities.String(); s != "GPS|BLE|RX" {
+		t.Fatalf("capabilities are formatted as %s, expected GPS|BLE|RX", s)
+	}
+}
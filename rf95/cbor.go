@@ -0,0 +1,136 @@
+package rf95
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// CBOR major types, RFC 8949, section 3.1.
+const (
+	cborUint   byte = 0
+	cborNegint byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborSimple byte = 7
+)
+
+// cborEncoder writes a minimal subset of CBOR, just enough for the telemetry
+// of constrained consumers, e.g., embedded displays or other LoRa nodes.
+type cborEncoder []byte
+
+// head of a data item of the major type with the argument n.
+func (enc *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		*enc = append(*enc, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		*enc = append(*enc, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		*enc = binary.BigEndian.AppendUint16(append(*enc, major<<5|25), uint16(n))
+	case n <= math.MaxUint32:
+		*enc = binary.BigEndian.AppendUint32(append(*enc, major<<5|26), uint32(n))
+	default:
+		*enc = binary.BigEndian.AppendUint64(append(*enc, major<<5|27), n)
+	}
+}
+
+func (enc *cborEncoder) int(i int64) {
+	if i < 0 {
+		enc.head(cborNegint, uint64(-1-i))
+	} else {
+		enc.head(cborUint, uint64(i))
+	}
+}
+
+func (enc *cborEncoder) bytes(b []byte) {
+	enc.head(cborBytes, uint64(len(b)))
+	*enc = append(*enc, b...)
+}
+
+func (enc *cborEncoder) text(s string) {
+	enc.head(cborText, uint64(len(s)))
+	*enc = append(*enc, s...)
+}
+
+func (enc *cborEncoder) bool(b bool) {
+	if b {
+		*enc = append(*enc, cborSimple<<5|21)
+	} else {
+		*enc = append(*enc, cborSimple<<5|20)
+	}
+}
+
+func (enc *cborEncoder) float(f float64) {
+	*enc = binary.BigEndian.AppendUint64(append(*enc, cborSimple<<5|27), math.Float64bits(f))
+}
+
+// MarshalCBOR encodes the RxMessage as a CBOR map.
+//
+// Its keys are "unix_nanosec", "payload", "rssi", and "snr", followed by
+// "latitude", "longitude", and "altitude" for a known Fix.
+func (rx RxMessage) MarshalCBOR() ([]byte, error) {
+	var enc cborEncoder
+
+	if rx.Fix == nil {
+		enc.head(cborMap, 4)
+	} else {
+		enc.head(cborMap, 7)
+	}
+
+	enc.text("unix_nanosec")
+	enc.int(rx.Time.UnixNano())
+	enc.text("payload")
+	enc.bytes(rx.Payload)
+	enc.text("rssi")
+	enc.int(int64(rx.Rssi))
+	enc.text("snr")
+	enc.int(int64(rx.Snr))
+
+	if rx.Fix != nil {
+		enc.text("latitude")
+		enc.float(rx.Fix.Latitude)
+		enc.text("longitude")
+		enc.float(rx.Fix.Longitude)
+		enc.text("altitude")
+		enc.float(rx.Fix.Altitude)
+	}
+
+	return enc, nil
+}
+
+// MarshalCBOR encodes the Status as a CBOR map, keyed like the AT+INFO output in snake case.
+func (status Status) MarshalCBOR() ([]byte, error) {
+	var enc cborEncoder
+
+	enc.head(cborMap, 11)
+
+	enc.text("firmware")
+	enc.text(status.Firmware)
+	enc.text("features")
+	enc.head(cborArray, uint64(len(status.Features)))
+	for _, feature := range status.Features {
+		enc.text(string(feature))
+	}
+	enc.text("modem_config")
+	enc.int(int64(status.Mode))
+	enc.text("max_pkt_size")
+	enc.int(int64(status.Mtu))
+	enc.text("frequency")
+	enc.float(status.Frequency)
+	enc.text("bfb")
+	enc.int(int64(status.Bfb))
+	enc.text("tx_power")
+	enc.int(int64(status.TxPower))
+	enc.text("rx_listener")
+	enc.bool(status.Receive)
+	enc.text("rx_bad")
+	enc.int(int64(status.RxBad))
+	enc.text("rx_good")
+	enc.int(int64(status.RxGood))
+	enc.text("tx_good")
+	enc.int(int64(status.TxGood))
+
+	return enc, nil
+}
@@ -0,0 +1,59 @@
+package rf95
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestCborEncoder(t *testing.T) {
+	// Test vectors from RFC 8949, appendix A.
+	tests := []struct {
+		encode  func(*cborEncoder)
+		encoded string
+	}{
+		{func(enc *cborEncoder) { enc.int(0) }, "00"},
+		{func(enc *cborEncoder) { enc.int(23) }, "17"},
+		{func(enc *cborEncoder) { enc.int(24) }, "1818"},
+		{func(enc *cborEncoder) { enc.int(1000) }, "1903e8"},
+		{func(enc *cborEncoder) { enc.int(1000000) }, "1a000f4240"},
+		{func(enc *cborEncoder) { enc.int(1000000000000) }, "1b000000e8d4a51000"},
+		{func(enc *cborEncoder) { enc.int(-1) }, "20"},
+		{func(enc *cborEncoder) { enc.int(-100) }, "3863"},
+		{func(enc *cborEncoder) { enc.bool(false) }, "f4"},
+		{func(enc *cborEncoder) { enc.bool(true) }, "f5"},
+		{func(enc *cborEncoder) { enc.float(1.1) }, "fb3ff199999999999a"},
+		{func(enc *cborEncoder) { enc.bytes([]byte{1, 2, 3, 4}) }, "4401020304"},
+		{func(enc *cborEncoder) { enc.text("IETF") }, "6449455446"},
+		{func(enc *cborEncoder) { enc.head(cborMap, 0) }, "a0"},
+	}
+
+	for _, test := range tests {
+		var enc cborEncoder
+		test.encode(&enc)
+
+		if encoded := hex.EncodeToString(enc); encoded != test.encoded {
+			t.Fatalf("encoded %s, expected %s", encoded, test.encoded)
+		}
+	}
+}
+
+func TestRxMessageMarshalCBOR(t *testing.T) {
+	rx := RxMessage{Payload: []byte("AAA"), Rssi: -15, Snr: 8, Time: time.Unix(0, 42)}
+
+	encoded, _ := rx.MarshalCBOR()
+	expected, _ := hex.DecodeString("a4" +
+		"6c756e69785f6e616e6f736563" + "182a" +
+		"677061796c6f6164" + "43414141" +
+		"6472737369" + "2e" +
+		"63736e72" + "08")
+	if !bytes.Equal(encoded, expected) {
+		t.Fatalf("encoded %x, expected %x", encoded, expected)
+	}
+
+	rx.Fix = &Fix{Position: Position{Latitude: 50.8, Longitude: 8.7}}
+	if encoded, _ := rx.MarshalCBOR(); encoded[0] != 0xa7 {
+		t.Fatalf("geotagged RX message is encoded as %x, expected a map of seven pairs", encoded)
+	}
+}
@@ -0,0 +1,30 @@
+package rf95
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Chaos degrades a Server's behavior towards a client, e.g., to verify that
+// applications survive a flaky gateway before their field deployment.
+type Chaos struct {
+	// Latency delays each line sent to the client.
+	Latency time.Duration
+
+	// DropRate is the probability of an RX line to be dropped.
+	DropRate float64
+
+	// DisconnectRate is the probability of the client to be disconnected
+	// instead of receiving a line.
+	DisconnectRate float64
+}
+
+// drop decides whether the next RX line is dropped.
+func (chaos *Chaos) drop() bool {
+	return chaos.DropRate > 0 && rand.Float64() < chaos.DropRate
+}
+
+// disconnect decides whether the client is disconnected instead of receiving the next line.
+func (chaos *Chaos) disconnect() bool {
+	return chaos.DisconnectRate > 0 && rand.Float64() < chaos.DisconnectRate
+}
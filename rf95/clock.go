@@ -0,0 +1,70 @@
+package rf95
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSync pairs a reading of the local wall clock with a trusted reference time, e.g., from GPS or NTP.
+type ClockSync struct {
+	Local     time.Time
+	Reference time.Time
+}
+
+// ClockCorrection maps times of the local wall clock onto a reference clock.
+//
+// It compensates both a constant offset and a linear drift of the local
+// clock, e.g., a gateway's RTC, and allows correcting the timelines of long
+// captures afterwards.
+type ClockCorrection struct {
+	origin time.Time
+
+	// offset in seconds at the origin and drift in seconds per second.
+	offset float64
+	drift  float64
+}
+
+// NewClockCorrection fits a ClockCorrection to the ClockSyncs by least squares.
+//
+// At least one ClockSync is required. A single one, or multiple ones at the
+// same local time, only correct the offset.
+func NewClockCorrection(syncs []ClockSync) (correction ClockCorrection, err error) {
+	if len(syncs) == 0 {
+		err = fmt.Errorf("at least one clock sync is required")
+		return
+	}
+
+	// Only the wall clock readings are compared, ignoring monotonic ones.
+	correction.origin = syncs[0].Local.Round(0)
+
+	var sumX, sumY, sumXX, sumXY float64
+	for _, sync := range syncs {
+		x := sync.Local.Round(0).Sub(correction.origin).Seconds()
+		y := sync.Reference.Round(0).Sub(sync.Local.Round(0)).Seconds()
+
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+
+	n := float64(len(syncs))
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		correction.drift = (n*sumXY - sumX*sumY) / denominator
+	}
+	correction.offset = (sumY - correction.drift*sumX) / n
+	return
+}
+
+// Correct a time of the local wall clock.
+func (correction ClockCorrection) Correct(t time.Time) time.Time {
+	t = t.Round(0)
+	x := t.Sub(correction.origin).Seconds()
+	return t.Add(time.Duration((correction.offset + correction.drift*x) * float64(time.Second)))
+}
+
+// CorrectRx returns the RxMessage with its Time corrected.
+func (correction ClockCorrection) CorrectRx(rx RxMessage) RxMessage {
+	rx.Time = correction.Correct(rx.Time)
+	return rx
+}
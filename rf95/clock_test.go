@@ -0,0 +1,59 @@
+package rf95
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockCorrection(t *testing.T) {
+	start := time.Date(2023, 8, 10, 12, 0, 0, 0, time.UTC)
+
+	// The local clock is two seconds ahead and gains one millisecond per second.
+	local := func(ref time.Time) time.Time {
+		return ref.Add(2*time.Second + ref.Sub(start)/1000)
+	}
+
+	var syncs []ClockSync
+	for i := 0; i < 4; i++ {
+		ref := start.Add(time.Duration(i) * time.Hour)
+		syncs = append(syncs, ClockSync{Local: local(ref), Reference: ref})
+	}
+
+	correction, err := NewClockCorrection(syncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := start.Add(90 * time.Minute)
+	if corrected := correction.Correct(local(ref)); corrected.Sub(ref).Abs() > time.Millisecond {
+		t.Fatalf("corrected time %v, expected %v", corrected, ref)
+	}
+
+	if _, err := NewClockCorrection(nil); err == nil {
+		t.Fatal("clock correction without syncs succeeded")
+	}
+
+	single, _ := NewClockCorrection(syncs[:1])
+	if corrected := single.Correct(local(start)); !corrected.Equal(start) {
+		t.Fatalf("corrected time %v, expected %v", corrected, start)
+	}
+}
+
+func TestRxMessageTime(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	rxChan := make(chan RxMessage, 1)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	dev.inject("+RX 3,414141,-15,8\n")
+	rx := <-rxChan
+
+	if rx.Time.Before(before) || rx.Time.After(time.Now()) {
+		t.Fatalf("RX time %v is not within the reception", rx.Time)
+	} else if rx.Monotonic <= 0 {
+		t.Fatalf("RX monotonic time %v is not positive", rx.Monotonic)
+	}
+}
@@ -0,0 +1,104 @@
+package rf95
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// atResponse describes the expected response of an AT command.
+type atResponse struct {
+	// terminal reports whether a line is the response's last one.
+	terminal func(line string) bool
+
+	// pattern must be matched by the response's last line. Its submatches are
+	// passed back as an atResult's match.
+	pattern *regexp.Regexp
+}
+
+// firstLine is a terminal function for single line responses.
+func firstLine(string) bool {
+	return true
+}
+
+// prefixLine creates a terminal function for responses ending with a line of any of the given prefixes.
+func prefixLine(prefixes ...string) func(string) bool {
+	return func(line string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// atResponses is the registry of all known AT commands, identified by their
+// name without arguments, and their expected responses.
+//
+// Supporting another command or another firmware's response format should only
+// require changes within this table or within another Dialect's one. AT+INFO
+// accepts the terminating lines of all Dialects to allow their detection.
+var atResponses = map[string]atResponse{
+	"AT+BFB":  {firstLine, regexp.MustCompile(`^\+OK`)},
+	"AT+FREQ": {firstLine, regexp.MustCompile(`^\+FREQ: (.+?)\r?\n$`)},
+	"AT+HELP": {prefixLine("+OK", "+FAIL"), regexp.MustCompile(`^\+OK`)},
+	"AT+INFO": {prefixLine("+OK", "+ Ok."), regexp.MustCompile(`^\+(?:OK| Ok\.)`)},
+	"AT+MODE": {firstLine, regexp.MustCompile(`^\+OK`)},
+	"AT+RX":   {firstLine, regexp.MustCompile(`^\+OK`)},
+	"AT+TX":   {firstLine, regexp.MustCompile(`^\+SENT (\d+) bytes\.\r?\n$`)},
+	"AT+TXP":  {firstLine, regexp.MustCompile(`^\+TXP: (-?\d+)\r?\n$`)},
+}
+
+// atResult is the response of an AT command, executed by Modem.execute.
+type atResult struct {
+	// lines are the raw response lines.
+	lines []string
+
+	// match are the submatches of the atResponse's pattern against the last line.
+	match []string
+}
+
+// atCommandName of an AT command, its part before the argument.
+func atCommandName(cmd string) string {
+	if i := strings.IndexRune(cmd, '='); i >= 0 {
+		return cmd[:i]
+	}
+	return cmd
+}
+
+// execute the AT commands, pipelined, and check their responses against the
+// atResponses registry of the Modem's Dialect.
+//
+// An unexpected response results in a ResponseError.
+func (modem *Modem) execute(cmds ...string) (results []atResult, err error) {
+	reqs := make([]atRequest, len(cmds))
+	resps := make([]atResponse, len(cmds))
+
+	for i, cmd := range cmds {
+		resp, ok := modem.Dialect().responses()[atCommandName(cmd)]
+		if !ok {
+			return nil, fmt.Errorf("AT command %s is not registered", atCommandName(cmd))
+		}
+
+		reqs[i] = atRequest{cmd, func(line string) bool { return !resp.terminal(line) }}
+		resps[i] = resp
+	}
+
+	responses, err := modem.atPipeline(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	results = make([]atResult, len(cmds))
+	for i, lines := range responses {
+		results[i].lines = lines
+		results[i].match = resps[i].pattern.FindStringSubmatch(lines[len(lines)-1])
+
+		if results[i].match == nil {
+			return nil, modem.responseError(cmds[i], lines, fmt.Errorf("unexpected response"))
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package rf95
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DetectTimeout bounds the probing of a single serial device by DetectModems.
+var DetectTimeout = 2 * time.Second
+
+// DetectedModem is a serial device which answered like a rf95modem.
+type DetectedModem struct {
+	Device string
+	Status Status
+}
+
+// DetectModems probes all serial devices listed by SerialPorts for a rf95modem.
+//
+// Each device is opened with the DefaultSerialConfig and queried by AT+INFO
+// within the DetectTimeout. The devices answering with a valid Status are
+// returned. Afterwards, all devices are closed again; a detected rf95modem
+// must be opened, e.g., by OpenSerial. Cancelling the Context aborts probing.
+func DetectModems(ctx context.Context) ([]DetectedModem, error) {
+	devices, err := SerialPorts()
+	if err != nil {
+		return nil, err
+	}
+
+	return detectModems(devices, OpenSerial, ctx), nil
+}
+
+// detectModems probes all devices in parallel, opened by the open function.
+func detectModems(devices []string, open func(string, context.Context) (*Modem, error), ctx context.Context) []DetectedModem {
+	probes := make([]*DetectedModem, len(devices))
+
+	var wg sync.WaitGroup
+	for i, device := range devices {
+		wg.Add(1)
+		go func(i int, device string) {
+			defer wg.Done()
+
+			probeCtx, probeCtxCancel := context.WithTimeout(ctx, DetectTimeout)
+			defer probeCtxCancel()
+
+			modem, err := open(device, probeCtx)
+			if err != nil {
+				return
+			}
+			defer func() { _ = modem.Close() }()
+
+			if status, err := modem.FetchStatus(); err == nil {
+				probes[i] = &DetectedModem{Device: device, Status: status}
+			}
+		}(i, device)
+	}
+	wg.Wait()
+
+	var detected []DetectedModem
+	for _, probe := range probes {
+		if probe != nil {
+			detected = append(detected, *probe)
+		}
+	}
+	return detected
+}
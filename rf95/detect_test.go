@@ -0,0 +1,47 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDetectModems(t *testing.T) {
+	defer func(timeout time.Duration) { DetectTimeout = timeout }(DetectTimeout)
+	DetectTimeout = 100 * time.Millisecond
+
+	responders := map[string]func(string) []string{
+		"/dev/ttyUSB0": func(string) []string { return nil },
+		"/dev/ttyUSB1": func(cmd string) []string {
+			if cmd == "AT+INFO" {
+				return testInfo
+			}
+			return nil
+		},
+		"/dev/ttyUSB2": func(string) []string { return []string{"garbage\n"} },
+	}
+
+	open := func(device string, ctx context.Context) (*Modem, error) {
+		respond, ok := responders[device]
+		if !ok {
+			return nil, fmt.Errorf("no such device %s", device)
+		}
+
+		dev := &testDevice{respond: respond}
+		dev.pipeReader, dev.pipeWriter = io.Pipe()
+		return OpenModem(dev.pipeReader, dev, dev, ctx)
+	}
+
+	devices := []string{"/dev/ttyUSB0", "/dev/ttyUSB1", "/dev/ttyUSB2", "/dev/ttyUSB3"}
+	detected := detectModems(devices, open, context.Background())
+
+	if len(detected) != 1 {
+		t.Fatalf("detected %d modems, expected one: %v", len(detected), detected)
+	} else if detected[0].Device != "/dev/ttyUSB1" {
+		t.Fatalf("detected modem at %s, expected /dev/ttyUSB1", detected[0].Device)
+	} else if detected[0].Status.Mtu != 251 {
+		t.Fatalf("detected modem has MTU %d, expected 251", detected[0].Status.Mtu)
+	}
+}
@@ -0,0 +1,72 @@
+package rf95
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Dialect of the rf95modem's responses, differing between firmware generations.
+type Dialect int
+
+const (
+	// CurrentDialect answers with, e.g., "+OK" and "+FREQ: 868.10".
+	CurrentDialect Dialect = iota
+
+	// LegacyDialect of older builds answers with, e.g., "+ Ok." and "Set Freq to: 868.10".
+	LegacyDialect
+)
+
+func (dialect Dialect) String() string {
+	if dialect == LegacyDialect {
+		return "legacy"
+	}
+	return "current"
+}
+
+// legacyAtResponses is the atResponses registry for the LegacyDialect.
+var legacyAtResponses = func() map[string]atResponse {
+	legacyOk := atResponse{firstLine, regexp.MustCompile(`^\+ Ok\.`)}
+
+	responses := make(map[string]atResponse, len(atResponses))
+	for name, resp := range atResponses {
+		responses[name] = resp
+	}
+
+	responses["AT+BFB"] = legacyOk
+	responses["AT+FREQ"] = atResponse{firstLine, regexp.MustCompile(`^Set Freq to: (.+?)\r?\n$`)}
+	responses["AT+HELP"] = atResponse{prefixLine("+ Ok.", "+FAIL"), legacyOk.pattern}
+	responses["AT+MODE"] = legacyOk
+	responses["AT+RX"] = legacyOk
+	return responses
+}()
+
+// responses is the atResponses registry of the Dialect.
+func (dialect Dialect) responses() map[string]atResponse {
+	if dialect == LegacyDialect {
+		return legacyAtResponses
+	}
+	return atResponses
+}
+
+// detectDialect by the terminating line of an AT+INFO response, which both Dialects share.
+func detectDialect(line string) Dialect {
+	if strings.HasPrefix(line, "+ Ok.") {
+		return LegacyDialect
+	}
+	return CurrentDialect
+}
+
+// Dialect of the rf95modem's responses.
+//
+// The Dialect is detected by each FetchStatus, including the MTU refresh of
+// RegisterHandlers and of each configuration change. Until then, the
+// CurrentDialect is assumed.
+func (modem *Modem) Dialect() Dialect {
+	return Dialect(atomic.LoadInt32(&modem.dialect))
+}
+
+// SetDialect overrides the detected Dialect of the rf95modem's responses until the next FetchStatus.
+func (modem *Modem) SetDialect(dialect Dialect) {
+	atomic.StoreInt32(&modem.dialect, int32(dialect))
+}
@@ -0,0 +1,51 @@
+package rf95
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModemLegacyDialect(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		switch {
+		case cmd == "AT+INFO":
+			info := append([]string{}, testInfo[:len(testInfo)-1]...)
+			return append(info, "+ Ok.\r\n")
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			return []string{"Set Freq to: " + strings.TrimPrefix(cmd, "AT+FREQ=") + "\r\n"}
+		case strings.HasPrefix(cmd, "AT+MODE="):
+			return []string{"+ Ok.\r\n"}
+		default:
+			return nil
+		}
+	})
+
+	if dialect := modem.Dialect(); dialect != CurrentDialect {
+		t.Fatalf("dialect before detection is %v, expected %v", dialect, CurrentDialect)
+	}
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+
+	if dialect := modem.Dialect(); dialect != LegacyDialect {
+		t.Fatalf("detected dialect is %v, expected %v", dialect, LegacyDialect)
+	}
+
+	if err := modem.Configure(868.5, FastShortRange); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModemCurrentDialect(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	modem.SetDialect(LegacyDialect)
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if dialect := modem.Dialect(); dialect != CurrentDialect {
+		t.Fatalf("detected dialect is %v, expected %v", dialect, CurrentDialect)
+	}
+}
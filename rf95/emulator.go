@@ -0,0 +1,265 @@
+package rf95
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// emulatorModeConfigs are the RadioHead modem config names of each ModemMode, as reported by AT+INFO.
+var emulatorModeConfigs = []string{
+	MediumRange:    "Bw125Cr45Sf128",
+	FastShortRange: "Bw500Cr45Sf128",
+	SlowLongRange:  "Bw31_25Cr48Sf512",
+	SlowLongRange2: "Bw125Cr48Sf4096",
+	SlowLongRange3: "Bw125Cr45Sf2048",
+}
+
+// emulatorHelp is the AT+HELP output of an emulated rf95modem.
+var emulatorHelp = []string{
+	"+HELP:\n",
+	"AT+HELP             Print this usage information.\n",
+	"AT+TX=<hexdata>     Send binary data.\n",
+	"AT+RX=<0|1>         Turn receiving on (1) or off (0).\n",
+	"AT+FREQ=<freq>      Changes the frequency.\n",
+	"AT+INFO             Output status information.\n",
+	"AT+MODE=<NUM>       Set modem config:\n",
+	"                    0 - medium range (default)\n",
+	"                    1 - fast+short range\n",
+	"                    2 - slow+long range\n",
+	"                    3 - slow+long range\n",
+	"                    4 - slow+long range\n",
+	"\n",
+	"+OK\n",
+}
+
+// Emulator is a reference implementation of the rf95modem's AT protocol.
+//
+// Each connection served by an Emulator behaves like an rf95modem of its own.
+// All of them share a virtual air: packets transmitted by one connection are
+// received by all others on the same frequency and ModemMode. Thus, firmware
+// and client developers, e.g., in other languages, might test against an
+// Emulator over TCP or a serial port instead of real hardware.
+type Emulator struct {
+	// Status is the initial Status of each emulated rf95modem.
+	Status Status
+
+	// Rssi and Snr are reported for each received packet.
+	Rssi int
+	Snr  int
+
+	modems      map[*emulatedModem]struct{}
+	modemsMutex sync.Mutex
+}
+
+// emulatedModem is the state of an rf95modem emulated on one connection.
+type emulatedModem struct {
+	conn       io.Writer
+	writeMutex sync.Mutex
+
+	// status and rx are protected by the Emulator's modemsMutex.
+	status Status
+	rx     bool
+
+	// rxLines are queued towards the connection; they are dropped if it is too slow.
+	rxLines chan string
+}
+
+// write the lines to the connection at once.
+func (emu *emulatedModem) write(lines ...string) error {
+	emu.writeMutex.Lock()
+	defer emu.writeMutex.Unlock()
+
+	_, err := emu.conn.Write([]byte(strings.Join(lines, "")))
+	return err
+}
+
+// NewEmulator of an rf95modem in MediumRange mode on 868.1 MHz.
+func NewEmulator() *Emulator {
+	return &Emulator{
+		Status: Status{
+			Firmware:  "0.7.3",
+			Features:  []Feature{FeatureLora},
+			Mode:      MediumRange,
+			Mtu:       251,
+			Frequency: 868.1,
+		},
+		Rssi:   -60,
+		Snr:    9,
+		modems: make(map[*emulatedModem]struct{}),
+	}
+}
+
+// Serve connections accepted from the net.Listener until it fails, each emulating an rf95modem.
+func (emulator *Emulator) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			_ = emulator.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn emulates an rf95modem on the connection, e.g., a serial port, until reading from it fails.
+func (emulator *Emulator) ServeConn(conn io.ReadWriter) error {
+	emu := &emulatedModem{conn: conn, rx: true, rxLines: make(chan string, 64)}
+
+	emulator.modemsMutex.Lock()
+	emu.status = emulator.Status
+	emulator.modems[emu] = struct{}{}
+	emulator.modemsMutex.Unlock()
+
+	defer func() {
+		emulator.modemsMutex.Lock()
+		delete(emulator.modems, emu)
+		emulator.modemsMutex.Unlock()
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case line := <-emu.rxLines:
+				_ = emu.write(line)
+			}
+		}
+	}()
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		cmd := strings.TrimSpace(line)
+		if cmd == "" {
+			continue
+		}
+
+		if err := emu.write(emulator.execute(emu, cmd)...); err != nil {
+			return err
+		}
+	}
+}
+
+// execute an AT command on the emulated rf95modem and return its response lines.
+func (emulator *Emulator) execute(emu *emulatedModem, cmd string) []string {
+	emulator.modemsMutex.Lock()
+	defer emulator.modemsMutex.Unlock()
+
+	name, arg, _ := strings.Cut(cmd, "=")
+
+	switch name {
+	case "AT+HELP":
+		return emulatorHelp
+
+	case "AT+INFO":
+		return emulatorInfo(emu.status, emu.rx)
+
+	case "AT+FREQ":
+		freq, err := strconv.ParseFloat(arg, 64)
+		if err != nil || freq <= 0 {
+			return []string{"+FAIL\n"}
+		}
+		emu.status.Frequency = freq
+		return []string{fmt.Sprintf("+FREQ: %.2f\n", freq)}
+
+	case "AT+MODE":
+		mode, err := strconv.Atoi(arg)
+		if err != nil || checkMode(ModemMode(mode)) != nil {
+			return []string{"+FAIL\n"}
+		}
+		emu.status.Mode = ModemMode(mode)
+		return []string{"+OK\n"}
+
+	case "AT+RX":
+		if arg != "0" && arg != "1" {
+			return []string{"+FAIL\n"}
+		}
+		emu.rx = arg == "1"
+		return []string{"+OK\n"}
+
+	case "AT+TX":
+		payload, err := hex.DecodeString(arg)
+		if err != nil || len(payload) == 0 || len(payload) > emu.status.Mtu {
+			return []string{"+FAIL\n"}
+		}
+		emu.status.TxGood++
+		emulator.transmit(emu, payload)
+		return []string{fmt.Sprintf("+SENT %d bytes.\n", len(payload))}
+
+	default:
+		return []string{"+FAIL\n"}
+	}
+}
+
+// transmit the payload from the sender to all other emulated rf95modems on the same channel.
+//
+// The Emulator's modemsMutex must be held.
+func (emulator *Emulator) transmit(sender *emulatedModem, payload []byte) {
+	line := fmt.Sprintf("+RX %d,%s,%d,%d\n", len(payload), strings.ToUpper(hex.EncodeToString(payload)), emulator.Rssi, emulator.Snr)
+
+	for emu := range emulator.modems {
+		if emu == sender || !emu.rx || emu.status.Mode != sender.status.Mode ||
+			fmt.Sprintf("%.2f", emu.status.Frequency) != fmt.Sprintf("%.2f", sender.status.Frequency) {
+			continue
+		}
+
+		select {
+		case emu.rxLines <- line:
+			emu.status.RxGood++
+		default:
+			emu.status.RxBad++
+		}
+	}
+}
+
+// emulatorInfo is the AT+INFO output of an emulated rf95modem.
+func emulatorInfo(status Status, rx bool) []string {
+	config := ""
+	if int(status.Mode) < len(emulatorModeConfigs) {
+		config = emulatorModeConfigs[status.Mode]
+	}
+
+	rxListener := 0
+	if rx {
+		rxListener = 1
+	}
+
+	features := make([]string, len(status.Features))
+	for i, feature := range status.Features {
+		features[i] = string(feature)
+	}
+
+	return []string{
+		"+STATUS:\n",
+		"\n",
+		fmt.Sprintf("firmware:      %s\n", status.Firmware),
+		fmt.Sprintf("features:      %s\n", strings.Join(features, " ")),
+		fmt.Sprintf("modem config:  %d | %s\n", status.Mode, config),
+		fmt.Sprintf("max pkt size:  %d\n", status.Mtu),
+		fmt.Sprintf("frequency:     %.2f\n", status.Frequency),
+		fmt.Sprintf("BFB:           %d\n", status.Bfb),
+		fmt.Sprintf("rx listener:   %d\n", rxListener),
+		"GPS:           0\n",
+		fmt.Sprintf("rx bad:        %d\n", status.RxBad),
+		fmt.Sprintf("rx good:       %d\n", status.RxGood),
+		fmt.Sprintf("tx good:       %d\n", status.TxGood),
+		"+OK\n",
+	}
+}
@@ -0,0 +1,71 @@
+package rf95
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestEmulatorHelp(t *testing.T) {
+	emulator := NewEmulator()
+	emu := &emulatedModem{status: emulator.Status}
+
+	if cmds := parseHelp(emulator.execute(emu, "AT+HELP")); len(cmds) != 6 {
+		t.Fatalf("emulated AT+HELP advertises %d commands, expected 6", len(cmds))
+	}
+}
+
+func TestEmulator(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() { _ = NewEmulator().Serve(listener) }()
+
+	var modems [3]*Modem
+	rxs := make(chan RxMessage, len(modems))
+	for i := range modems {
+		modem, err := OpenTransport(NewTransport(func(ctx context.Context) (conn io.ReadWriteCloser, err error) {
+			return (&net.Dialer{}).DialContext(ctx, "tcp", listener.Addr().String())
+		}), context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer modem.Close()
+
+		if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxs <- rx }, nil); err != nil {
+			t.Fatal(err)
+		}
+		modems[i] = modem
+	}
+
+	// The third rf95modem tunes into another channel and must not receive anything.
+	if err := modems[2].Configure(869.5, MediumRange); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := modems[0].Transmit([]byte("AAA")); err != nil {
+		t.Fatal(err)
+	}
+
+	if rx := <-rxs; string(rx.Payload) != "AAA" || rx.Rssi != -60 || rx.Snr != 9 {
+		t.Fatalf("received %v, expected AAA", rx)
+	}
+
+	if status, err := modems[1].FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.RxGood != 1 || status.Mtu != 251 {
+		t.Fatalf("receiver's status is %#v", status)
+	}
+	if status, err := modems[2].FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.RxGood != 0 || status.Frequency != 869.5 {
+		t.Fatalf("status of the other channel is %#v", status)
+	}
+	if len(rxs) != 0 {
+		t.Fatalf("received %d additional messages", len(rxs))
+	}
+}
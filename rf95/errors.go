@@ -0,0 +1,49 @@
+package rf95
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResponseError describes an unexpected response of the rf95modem to an AT command.
+//
+// Next to the underlying reason, it holds the raw lines involved, which makes
+// errors such as an unexpected response actionable. Use errors.As to access it.
+type ResponseError struct {
+	// Command is the AT command sent to the rf95modem.
+	Command string
+
+	// Lines are the raw lines read back as the command's response.
+	Lines []string
+
+	// Queued is the number of further lines waiting in the Modem's queue. A
+	// non-zero value hints at responses being out of sync with their commands.
+	Queued int
+
+	// Err is the underlying reason.
+	Err error
+}
+
+func (e *ResponseError) Error() string {
+	lines := make([]string, len(e.Lines))
+	for i, line := range e.Lines {
+		lines[i] = fmt.Sprintf("%q", line)
+	}
+
+	return fmt.Sprintf("%s: %v (response: %s; %d lines queued)",
+		e.Command, e.Err, strings.Join(lines, ", "), e.Queued)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// responseError wraps err with the context of the AT command's response.
+func (modem *Modem) responseError(cmd string, lines []string, err error) error {
+	return &ResponseError{
+		Command: cmd,
+		Lines:   lines,
+		Queued:  len(modem.msgQueue),
+		Err:     err,
+	}
+}
@@ -0,0 +1,40 @@
+package rf95
+
+import "strings"
+
+// Feature of an rf95modem's firmware build, as listed by AT+INFO.
+type Feature string
+
+const (
+	// FeatureLora is the LoRa radio, present in each build.
+	FeatureLora Feature = "LORA"
+
+	// FeatureGps is an onboard GPS receiver.
+	FeatureGps Feature = "GPS"
+
+	// FeatureBle is a Bluetooth Low Energy interface.
+	FeatureBle Feature = "BLE"
+
+	// FeatureWifi is a WiFi interface, e.g., of an ESP32.
+	FeatureWifi Feature = "WIFI"
+)
+
+// ParseFeatures of AT+INFO's space separated features list, e.g., "LORA GPS BLE".
+//
+// Unknown features are kept as they are.
+func ParseFeatures(list string) (features []Feature) {
+	for _, field := range strings.Fields(list) {
+		features = append(features, Feature(field))
+	}
+	return
+}
+
+// HasFeature checks if the rf95modem's firmware was built with the Feature.
+func (status Status) HasFeature(feature Feature) bool {
+	for _, f := range status.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
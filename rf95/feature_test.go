@@ -0,0 +1,37 @@
+package rf95
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	tests := []struct {
+		list     string
+		features []Feature
+	}{
+		{"LORA GPS BLE", []Feature{FeatureLora, FeatureGps, FeatureBle}},
+		{" LORA  WIFI\r", []Feature{FeatureLora, FeatureWifi}},
+		{"LORA FOO", []Feature{FeatureLora, Feature("FOO")}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		if features := ParseFeatures(test.list); !reflect.DeepEqual(features, test.features) {
+			t.Fatalf("features list %q parsed to %v, expected %v", test.list, features, test.features)
+		}
+	}
+}
+
+func TestStatusHasFeature(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	status, err := modem.FetchStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !status.HasFeature(FeatureGps) || !status.HasFeature(FeatureBle) || status.HasFeature(FeatureWifi) {
+		t.Fatalf("status with features %v reports them wrongly", status.Features)
+	}
+}
@@ -0,0 +1,112 @@
+package rf95
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// defaultFrequencyDigits is the rf95modem's native precision of 10 kHz.
+	defaultFrequencyDigits = 2
+
+	// maxFrequencyDigits allows a precision down to 1 Hz.
+	maxFrequencyDigits = 6
+
+	// maxCorrectionHz and maxCorrectionPpm bound a frequency correction.
+	maxCorrectionHz  = 100e3
+	maxCorrectionPpm = 100
+)
+
+// frequencyCorrection is a board's frequency error to be compensated.
+type frequencyCorrection struct {
+	hz  float64
+	ppm float64
+}
+
+// apply the correction to a nominal frequency in MHz.
+func (correction frequencyCorrection) apply(frequency float64) float64 {
+	return frequency*(1+correction.ppm/1e6) + correction.hz/1e6
+}
+
+// revert the correction of a tuned frequency in MHz, resulting in the nominal one.
+func (correction frequencyCorrection) revert(frequency float64) float64 {
+	return (frequency - correction.hz/1e6) / (1 + correction.ppm/1e6)
+}
+
+// SetFrequencyCorrection compensates the board's frequency error, e.g., of a
+// cheap crystal drifting with temperature, by an offset in Hz and in ppm.
+//
+// The correction is folded into each following frequency change, e.g., by
+// Frequency or Configure; the current frequency is not changed. Conversely,
+// the Status reports the nominal frequency without the correction. As the
+// default precision rounds to 10 kHz, SetFrequencyPrecision should be raised
+// accordingly. Zero values remove the correction.
+func (modem *Modem) SetFrequencyCorrection(hz, ppm float64) error {
+	if math.Abs(hz) > maxCorrectionHz {
+		return fmt.Errorf("frequency correction %.0f Hz is not in [%.0f, %.0f]", hz, -maxCorrectionHz, maxCorrectionHz)
+	} else if math.Abs(ppm) > maxCorrectionPpm {
+		return fmt.Errorf("frequency correction %.2f ppm is not in [%d, %d]", ppm, -maxCorrectionPpm, maxCorrectionPpm)
+	}
+
+	modem.frequencyMutex.Lock()
+	defer modem.frequencyMutex.Unlock()
+
+	modem.frequencyCorrection = frequencyCorrection{hz: hz, ppm: ppm}
+	return nil
+}
+
+// correction currently set by SetFrequencyCorrection.
+func (modem *Modem) correction() frequencyCorrection {
+	modem.frequencyMutex.Lock()
+	defer modem.frequencyMutex.Unlock()
+
+	return modem.frequencyCorrection
+}
+
+// SetFrequencyPrecision sets the number of decimal places of frequencies in MHz sent by AT+FREQ.
+//
+// The default of two places rounds to 10 kHz, which is too coarse for narrow
+// channel plans. Other values require a firmware accepting them. As each
+// frequency is read back, a firmware rounding to a coarser precision than
+// requested results in a ResponseError.
+func (modem *Modem) SetFrequencyPrecision(digits int) error {
+	if digits < 0 || digits > maxFrequencyDigits {
+		return fmt.Errorf("frequency precision %d is not in [0, %d]", digits, maxFrequencyDigits)
+	}
+
+	atomic.StoreInt32(&modem.frequencyDigits, int32(digits))
+	return nil
+}
+
+// frequencyCmd is the AT+FREQ command for the corrected frequency in MHz, formatted with the configured precision.
+func (modem *Modem) frequencyCmd(frequency float64) string {
+	return fmt.Sprintf("AT+FREQ=%.*f", atomic.LoadInt32(&modem.frequencyDigits), frequency)
+}
+
+// verifyFrequency checks the frequency read back as the AT+FREQ command's result against the requested one.
+//
+// Both are compared within the coarser precision of the sent and the read back
+// frequency, as the firmware might print fewer decimal places than it applies.
+func (modem *Modem) verifyFrequency(cmd string, frequency float64, result atResult) error {
+	readback := strings.TrimSpace(result.match[1])
+	applied, err := strconv.ParseFloat(readback, 64)
+	if err != nil {
+		return modem.responseError(cmd, result.lines, err)
+	}
+
+	digits := int(atomic.LoadInt32(&modem.frequencyDigits))
+	if _, decimals, ok := strings.Cut(readback, "."); !ok {
+		digits = 0
+	} else if len(decimals) < digits {
+		digits = len(decimals)
+	}
+
+	if tolerance := 0.5*math.Pow10(-digits) + 1e-9; math.Abs(applied-frequency) > tolerance {
+		return modem.responseError(cmd, result.lines,
+			fmt.Errorf("frequency %s MHz was applied, expected %s", readback, strings.TrimPrefix(cmd, "AT+FREQ=")))
+	}
+	return nil
+}
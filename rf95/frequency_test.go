@@ -0,0 +1,99 @@
+package rf95
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestModemFrequencyPrecision(t *testing.T) {
+	// The firmware applies frequencies with a precision of 100 Hz.
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		if freq := strings.TrimPrefix(cmd, "AT+FREQ="); freq != cmd {
+			if len(freq) > len("868.1234") {
+				freq = freq[:len("868.1234")]
+			}
+			return []string{"+FREQ: " + freq + "\n"}
+		}
+		return nil
+	})
+
+	if err := modem.SetFrequencyPrecision(7); err == nil {
+		t.Fatalf("a precision of seven decimal places did not error")
+	}
+
+	tests := []struct {
+		digits    int
+		frequency float64
+		cmd       string
+		applied   bool
+	}{
+		{2, 868.1234, "AT+FREQ=868.12", true},
+		{4, 868.1234, "AT+FREQ=868.1234", true},
+		{6, 868.123456, "AT+FREQ=868.123456", false},
+	}
+
+	for _, test := range tests {
+		cmds = nil
+
+		if err := modem.SetFrequencyPrecision(test.digits); err != nil {
+			t.Fatal(err)
+		}
+
+		var respErr *ResponseError
+		if err := modem.Frequency(test.frequency); test.applied && err != nil {
+			t.Fatalf("frequency %f with %d decimal places errored: %v", test.frequency, test.digits, err)
+		} else if !test.applied && !errors.As(err, &respErr) {
+			t.Fatalf("frequency %f with %d decimal places returned %v, expected a ResponseError", test.frequency, test.digits, err)
+		}
+
+		if cmds[0] != test.cmd {
+			t.Fatalf("command is %s, expected %s", cmds[0], test.cmd)
+		}
+	}
+}
+
+func TestModemFrequencyCorrection(t *testing.T) {
+	var tuned string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		switch {
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			tuned = strings.TrimPrefix(cmd, "AT+FREQ=")
+			return []string{"+FREQ: " + tuned + "\n"}
+		case cmd == "AT+INFO" && tuned != "":
+			info := append([]string{}, testInfo...)
+			info[6] = "frequency:     " + tuned + "\n"
+			return info
+		default:
+			return nil
+		}
+	})
+
+	for _, test := range []struct{ hz, ppm float64 }{{100e3 + 1, 0}, {0, -100.5}} {
+		if err := modem.SetFrequencyCorrection(test.hz, test.ppm); err == nil {
+			t.Fatalf("frequency correction of %.0f Hz and %.1f ppm did not error", test.hz, test.ppm)
+		}
+	}
+
+	if err := modem.SetFrequencyPrecision(4); err != nil {
+		t.Fatal(err)
+	} else if err := modem.SetFrequencyCorrection(-1000, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	// 868.1 MHz + 10 ppm (8681 Hz) - 1000 Hz = 868.107681 MHz
+	if err := modem.Frequency(868.1); err != nil {
+		t.Fatal(err)
+	} else if tuned != "868.1077" {
+		t.Fatalf("tuned to %s MHz, expected 868.1077", tuned)
+	}
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(status.Frequency-868.1) > 1e-4 {
+		t.Fatalf("status reports %f MHz, expected the nominal 868.1", status.Frequency)
+	}
+}
@@ -0,0 +1,101 @@
+package rf95
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Command is an AT command advertised by the rf95modem's AT+HELP.
+type Command struct {
+	// Name of the command, e.g., AT+FREQ.
+	Name string
+
+	// Argument form as advertised, e.g., <freq>. Empty if no argument is taken.
+	Argument string
+
+	// Description of the command, possibly spanning multiple lines.
+	Description string
+}
+
+// helpCommandRegexp matches the first line of a command within the AT+HELP output.
+var helpCommandRegexp = regexp.MustCompile(`^(AT\+[A-Z0-9]+)(?:=(\S*))?\s*(.*?)\r?\n?$`)
+
+// parseHelp extracts the advertised Commands from the AT+HELP output.
+//
+// Each command starts in a line of its own, followed by its description.
+// Indented lines continue the previous command's description.
+func parseHelp(lines []string) (cmds []Command) {
+	for _, line := range lines {
+		if fields := helpCommandRegexp.FindStringSubmatch(line); fields != nil {
+			cmds = append(cmds, Command{Name: fields[1], Argument: fields[2], Description: fields[3]})
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if len(cmds) == 0 || trimmed == "" || strings.HasPrefix(trimmed, "+") {
+			continue
+		}
+
+		if last := &cmds[len(cmds)-1]; last.Description == "" {
+			last.Description = trimmed
+		} else {
+			last.Description += "\n" + trimmed
+		}
+	}
+
+	return
+}
+
+// Commands returns the catalog of AT commands the rf95modem supports, as advertised by AT+HELP.
+//
+// The catalog is queried once and cached afterwards. As another device might
+// run another firmware, the cache is dropped after a reconnect or
+// SwapTransport. Call RefreshCommands after updating the firmware in place.
+func (modem *Modem) Commands() ([]Command, error) {
+	modem.commandsMutex.Lock()
+	defer modem.commandsMutex.Unlock()
+
+	if modem.commands != nil {
+		return modem.commands, nil
+	}
+
+	results, err := modem.execute("AT+HELP")
+	if err != nil {
+		return nil, err
+	}
+
+	modem.commands = parseHelp(results[0].lines)
+	return modem.commands, nil
+}
+
+// RefreshCommands drops the cached catalog of AT commands and queries it again, see Commands.
+func (modem *Modem) RefreshCommands() ([]Command, error) {
+	modem.forgetCommands()
+	return modem.Commands()
+}
+
+// forgetCommands drops the cached catalog of AT commands.
+func (modem *Modem) forgetCommands() {
+	modem.commandsMutex.Lock()
+	defer modem.commandsMutex.Unlock()
+
+	modem.commands = nil
+}
+
+// Supports checks if the rf95modem advertises the named AT command, e.g., AT+BFB.
+//
+// Optional features should be gated by this check instead of relying on
+// assumptions about the firmware version.
+func (modem *Modem) Supports(name string) (bool, error) {
+	cmds, err := modem.Commands()
+	if err != nil {
+		return false, err
+	}
+
+	for _, cmd := range cmds {
+		if cmd.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package rf95
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testHelp is an exemplary AT+HELP response of a rf95modem.
+var testHelp = []string{
+	"+HELP:\n",
+	"AT+HELP             Print this usage information.\n",
+	"AT+TX=<hexdata>     Send binary data.\n",
+	"AT+RX=<0|1>         Turn receiving on (1) or off (0).\n",
+	"AT+FREQ=<freq>      Changes the frequency.\n",
+	"AT+INFO             Output status information.\n",
+	"AT+MODE=<NUM>       Set modem config:\n",
+	"                    0 - medium range (default)\n",
+	"                    1 - fast+short range\n",
+	"\n",
+	"+OK\n",
+}
+
+func TestParseHelp(t *testing.T) {
+	expected := []Command{
+		{"AT+HELP", "", "Print this usage information."},
+		{"AT+TX", "<hexdata>", "Send binary data."},
+		{"AT+RX", "<0|1>", "Turn receiving on (1) or off (0)."},
+		{"AT+FREQ", "<freq>", "Changes the frequency."},
+		{"AT+INFO", "", "Output status information."},
+		{"AT+MODE", "<NUM>", "Set modem config:\n0 - medium range (default)\n1 - fast+short range"},
+	}
+
+	if cmds := parseHelp(testHelp); !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("parsed commands are %#v, expected %#v", cmds, expected)
+	}
+}
+
+func TestModemSupports(t *testing.T) {
+	helpQueries := 0
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if cmd == "AT+HELP" {
+			helpQueries++
+			return testHelp
+		}
+		return nil
+	})
+
+	for _, test := range []struct {
+		name      string
+		supported bool
+	}{
+		{"AT+RX", true},
+		{"AT+MODE", true},
+		{"AT+BFB", false},
+	} {
+		if supported, err := modem.Supports(test.name); err != nil {
+			t.Fatal(err)
+		} else if supported != test.supported {
+			t.Fatalf("support of %s is %t, expected %t", test.name, supported, test.supported)
+		}
+	}
+
+	if helpQueries != 1 {
+		t.Fatalf("AT+HELP was queried %d times, expected once", helpQueries)
+	}
+
+	if cmds, err := modem.RefreshCommands(); err != nil {
+		t.Fatal(err)
+	} else if len(cmds) != 6 {
+		t.Fatalf("refreshed catalog holds %d commands, expected 6", len(cmds))
+	} else if helpQueries != 2 {
+		t.Fatalf("AT+HELP was queried %d times after a refresh, expected twice", helpQueries)
+	}
+}
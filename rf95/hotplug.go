@@ -0,0 +1,91 @@
+package rf95
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultHotplugInterval is the HotplugWatcher's polling interval, unless set otherwise.
+const DefaultHotplugInterval = time.Second
+
+// HotplugWatcher opens a Modem when its serial device appears and closes it when the device disappears.
+//
+// This allows replugging a board, e.g., in kiosk-style deployments. The device
+// is polled at the Interval on all platforms.
+type HotplugWatcher struct {
+	// Find resolves the device's current name or fails if it is absent.
+	Find func() (string, error)
+
+	// Open the found device, e.g., OpenSerial.
+	Open func(string, context.Context) (*Modem, error)
+
+	// Interval between two polls, DefaultHotplugInterval if not positive.
+	Interval time.Duration
+
+	// OnAttach and OnDetach are called, if not nil, after a Modem was opened
+	// and before it is closed.
+	OnAttach func(*Modem)
+	OnDetach func(*Modem)
+}
+
+// NewHotplugWatcher for the serial device of the id, as resolved by FindSerialById, polling every DefaultHotplugInterval.
+func NewHotplugWatcher(id string, onAttach, onDetach func(*Modem)) *HotplugWatcher {
+	return &HotplugWatcher{
+		Find:     func() (string, error) { return FindSerialById(id) },
+		Open:     OpenSerial,
+		Interval: DefaultHotplugInterval,
+		OnAttach: onAttach,
+		OnDetach: onDetach,
+	}
+}
+
+// Watch the device until the Context is done, which also closes an opened Modem.
+func (watcher *HotplugWatcher) Watch(ctx context.Context) {
+	var (
+		modem  *Modem
+		device string
+	)
+
+	detach := func() {
+		if watcher.OnDetach != nil {
+			watcher.OnDetach(modem)
+		}
+		_ = modem.Close()
+		modem = nil
+	}
+
+	interval := watcher.Interval
+	if interval <= 0 {
+		interval = DefaultHotplugInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		found, findErr := watcher.Find()
+
+		if modem != nil && (findErr != nil || found != device || modem.ctx.Err() != nil) {
+			detach()
+		}
+
+		if modem == nil && findErr == nil {
+			if opened, openErr := watcher.Open(found, ctx); openErr == nil {
+				modem, device = opened, found
+				if watcher.OnAttach != nil {
+					watcher.OnAttach(modem)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if modem != nil {
+				detach()
+			}
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHotplugWatcher(t *testing.T) {
+	var plugged int32
+
+	events := make(chan string, 4)
+	watcher := &HotplugWatcher{
+		Find: func() (string, error) {
+			if atomic.LoadInt32(&plugged) == 0 {
+				return "", fmt.Errorf("unplugged")
+			}
+			return "/dev/ttyUSB0", nil
+		},
+		Open: func(device string, ctx context.Context) (*Modem, error) {
+			dev := &testDevice{respond: func(string) []string { return nil }}
+			dev.pipeReader, dev.pipeWriter = io.Pipe()
+			return OpenModem(dev.pipeReader, dev, dev, ctx)
+		},
+		Interval: 10 * time.Millisecond,
+		OnAttach: func(*Modem) { events <- "attach" },
+		OnDetach: func(*Modem) { events <- "detach" },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watcher.Watch(ctx)
+		close(done)
+	}()
+
+	expect := func(expected string) {
+		select {
+		case event := <-events:
+			if event != expected {
+				t.Fatalf("got event %s, expected %s", event, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing event %s", expected)
+		}
+	}
+
+	atomic.StoreInt32(&plugged, 1)
+	expect("attach")
+
+	atomic.StoreInt32(&plugged, 0)
+	expect("detach")
+
+	atomic.StoreInt32(&plugged, 1)
+	expect("attach")
+
+	cancel()
+	expect("detach")
+	<-done
+
+	// A zero Interval falls back to the default instead of panicking.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	(&HotplugWatcher{Find: watcher.Find, Open: watcher.Open}).Watch(ctx)
+}
@@ -0,0 +1,241 @@
+package rf95
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Interference is a likely interference condition, classified by an InterferenceMonitor.
+type Interference int
+
+const (
+	// NoInterference means the channel behaves like the quietest observed period.
+	NoInterference Interference = iota
+
+	// CoChannelLoRa indicates other LoRa transmissions on the same channel.
+	// Packets are detected but fail their CRC, while the noise floor stays low.
+	CoChannelLoRa
+
+	// WidebandNoise indicates a raised noise floor, e.g., from switching power
+	// supplies or other broadband emitters close to the antenna.
+	WidebandNoise
+
+	// Desense indicates a desensitized receiver, e.g., from a strong transmitter
+	// nearby. Fewer packets are received and only the strong ones get through.
+	Desense
+)
+
+func (i Interference) String() string {
+	switch i {
+	case NoInterference:
+		return "no interference"
+	case CoChannelLoRa:
+		return "co-channel LoRa"
+	case WidebandNoise:
+		return "wideband noise"
+	case Desense:
+		return "desense"
+	default:
+		return fmt.Sprintf("Interference(%d)", int(i))
+	}
+}
+
+// interferencePeriod summarizes the observations between two Status snapshots.
+type interferencePeriod struct {
+	packets  int
+	noiseSum float64
+	rssiSum  float64
+
+	rxGood   int
+	rxBad    int
+	duration time.Duration
+}
+
+// noise is the mean estimated noise floor, RSSI minus SNR, of all packets.
+func (period interferencePeriod) noise() float64 {
+	return period.noiseSum / float64(period.packets)
+}
+
+// rssi is the mean RSSI of all packets.
+func (period interferencePeriod) rssi() float64 {
+	return period.rssiSum / float64(period.packets)
+}
+
+// rate of good packets per second.
+func (period interferencePeriod) rate() float64 {
+	return float64(period.rxGood) / period.duration.Seconds()
+}
+
+// InterferenceMonitor classifies likely interference conditions from received
+// packets and the rf95modem's RX counters.
+//
+// RxMessages are fed through HandleRx, which might be registered as an RX
+// handler, and Status snapshots through HandleStatus. Each snapshot closes an
+// observation period, which is compared against the quietest period seen so
+// far. Changes of the classification are reported to the alert function.
+// Watch wires everything up for a Modem.
+type InterferenceMonitor struct {
+	// NoiseThreshold is the rise in dB of the noise floor or of the mean RSSI
+	// compared to the quietest period, which is considered significant.
+	NoiseThreshold float64
+
+	// BadRatioThreshold is the share of bad packets, in [0, 1], from which
+	// co-channel LoRa traffic is assumed.
+	BadRatioThreshold float64
+
+	alert func(Interference)
+
+	mutex      sync.Mutex
+	current    interferencePeriod
+	baseline   *interferencePeriod
+	lastStatus *Status
+	lastTime   time.Time
+	state      Interference
+}
+
+// NewInterferenceMonitor with default thresholds, reporting changes to alert.
+//
+// The alert function might be nil if only State is queried.
+func NewInterferenceMonitor(alert func(Interference)) *InterferenceMonitor {
+	return &InterferenceMonitor{
+		NoiseThreshold:    6,
+		BadRatioThreshold: 0.5,
+		alert:             alert,
+	}
+}
+
+// Watch registers the InterferenceMonitor as a handler at the Modem and polls
+// the Modem's Status at the given interval until the Modem is finished. The
+// interval must be positive.
+func (monitor *InterferenceMonitor) Watch(modem *Modem, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("polling interval %v must be positive", interval)
+	}
+
+	ctx, err := modem.RegisterHandlers(monitor.HandleRx, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				modem.FetchStatusAsync(func(status Status, statusErr error) {
+					if statusErr == nil {
+						monitor.HandleStatus(status)
+					}
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// HandleRx records a received message for the current observation period.
+func (monitor *InterferenceMonitor) HandleRx(rx RxMessage) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	monitor.current.packets++
+	monitor.current.noiseSum += float64(rx.Rssi - rx.Snr)
+	monitor.current.rssiSum += float64(rx.Rssi)
+}
+
+// HandleStatus closes the current observation period and classifies it.
+//
+// If the RX counters went backwards, e.g., after a reboot of the rf95modem,
+// the period is skipped and the counters become the new reference.
+func (monitor *InterferenceMonitor) HandleStatus(status Status) {
+	now := time.Now()
+
+	monitor.mutex.Lock()
+
+	if monitor.lastStatus == nil {
+		monitor.lastStatus, monitor.lastTime = &status, now
+		monitor.current = interferencePeriod{}
+		monitor.mutex.Unlock()
+		return
+	}
+
+	period := monitor.current
+	period.rxGood = status.RxGood - monitor.lastStatus.RxGood
+	period.rxBad = status.RxBad - monitor.lastStatus.RxBad
+	period.duration = now.Sub(monitor.lastTime)
+
+	monitor.lastStatus, monitor.lastTime = &status, now
+	monitor.current = interferencePeriod{}
+
+	if period.rxGood < 0 || period.rxBad < 0 {
+		monitor.mutex.Unlock()
+		return
+	}
+
+	state, ok := monitor.classify(period)
+	changed := ok && state != monitor.state
+	if changed {
+		monitor.state = state
+	}
+
+	monitor.mutex.Unlock()
+
+	if changed && monitor.alert != nil {
+		monitor.alert(state)
+	}
+}
+
+// State returns the latest classification.
+func (monitor *InterferenceMonitor) State() Interference {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	return monitor.state
+}
+
+// classify a finished period against the baseline, which might be updated.
+//
+// The second return value is false if the period holds no evidence at all.
+func (monitor *InterferenceMonitor) classify(period interferencePeriod) (Interference, bool) {
+	if period.rxGood+period.rxBad == 0 || period.duration <= 0 {
+		return NoInterference, false
+	}
+
+	badRatio := float64(period.rxBad) / float64(period.rxGood+period.rxBad)
+
+	if period.packets == 0 {
+		if badRatio >= monitor.BadRatioThreshold {
+			return CoChannelLoRa, true
+		}
+		return NoInterference, false
+	}
+
+	if monitor.baseline == nil || period.noise() < monitor.baseline.noise() {
+		monitor.baseline = &period
+		if badRatio >= monitor.BadRatioThreshold {
+			return CoChannelLoRa, true
+		}
+		return NoInterference, true
+	}
+
+	switch base := monitor.baseline; {
+	case period.noise()-base.noise() >= monitor.NoiseThreshold:
+		return WidebandNoise, true
+
+	case badRatio >= monitor.BadRatioThreshold:
+		return CoChannelLoRa, true
+
+	case period.rate() < base.rate()/2 && period.rssi()-base.rssi() >= monitor.NoiseThreshold:
+		return Desense, true
+
+	default:
+		return NoInterference, true
+	}
+}
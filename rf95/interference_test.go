@@ -0,0 +1,86 @@
+package rf95
+
+import (
+	"testing"
+	"time"
+)
+
+// interferenceTestPeriod creates an interferencePeriod of n packets with the same RSSI and SNR.
+func interferenceTestPeriod(n, rssi, snr, rxGood, rxBad int) interferencePeriod {
+	return interferencePeriod{
+		packets:  n,
+		noiseSum: float64(n * (rssi - snr)),
+		rssiSum:  float64(n * rssi),
+		rxGood:   rxGood,
+		rxBad:    rxBad,
+		duration: 10 * time.Second,
+	}
+}
+
+func TestInterferenceMonitorClassify(t *testing.T) {
+	baseline := interferenceTestPeriod(10, -100, 10, 10, 0)
+
+	tests := []struct {
+		name   string
+		period interferencePeriod
+		ok     bool
+		state  Interference
+	}{
+		{"quiet", interferenceTestPeriod(10, -98, 10, 10, 1), true, NoInterference},
+		{"no evidence", interferencePeriod{duration: 10 * time.Second}, false, NoInterference},
+		{"collisions", interferenceTestPeriod(4, -95, 12, 4, 6), true, CoChannelLoRa},
+		{"collisions without packets", interferenceTestPeriod(0, 0, 0, 0, 5), true, CoChannelLoRa},
+		{"noise floor", interferenceTestPeriod(10, -95, -2, 10, 0), true, WidebandNoise},
+		{"desense", interferenceTestPeriod(2, -80, 28, 2, 0), true, Desense},
+	}
+
+	for _, test := range tests {
+		monitor := NewInterferenceMonitor(nil)
+		if _, ok := monitor.classify(baseline); !ok {
+			t.Fatalf("%s: baseline was not accepted", test.name)
+		}
+
+		if state, ok := monitor.classify(test.period); ok != test.ok {
+			t.Fatalf("%s: classification returned ok %t, expected %t", test.name, ok, test.ok)
+		} else if ok && state != test.state {
+			t.Fatalf("%s: classification returned %v, expected %v", test.name, state, test.state)
+		}
+	}
+}
+
+func TestInterferenceMonitorAlert(t *testing.T) {
+	var alerts []Interference
+	monitor := NewInterferenceMonitor(func(i Interference) { alerts = append(alerts, i) })
+
+	monitor.HandleStatus(Status{})
+	monitor.HandleStatus(Status{RxGood: 0, RxBad: 8})
+	monitor.HandleStatus(Status{RxGood: 0, RxBad: 16})
+
+	if len(alerts) != 1 || alerts[0] != CoChannelLoRa {
+		t.Fatalf("alerts are %v, expected exactly one %v", alerts, CoChannelLoRa)
+	}
+	if state := monitor.State(); state != CoChannelLoRa {
+		t.Fatalf("state is %v, expected %v", state, CoChannelLoRa)
+	}
+}
+
+func TestInterferenceMonitorReboot(t *testing.T) {
+	var alerts []Interference
+	monitor := NewInterferenceMonitor(func(i Interference) { alerts = append(alerts, i) })
+
+	monitor.HandleStatus(Status{RxGood: 100, RxBad: 2})
+	monitor.HandleStatus(Status{RxGood: 110, RxBad: 2})
+
+	// The rf95modem rebooted and starts counting from zero.
+	monitor.HandleStatus(Status{RxGood: 0, RxBad: 4})
+	monitor.HandleStatus(Status{RxGood: 10, RxBad: 4})
+
+	if len(alerts) != 0 {
+		t.Fatalf("alerts are %v, expected none", alerts)
+	}
+
+	modem, _ := newTestModem(t, nil)
+	if err := monitor.Watch(modem, 0); err == nil {
+		t.Fatal("watching without a polling interval did not error")
+	}
+}
@@ -0,0 +1,161 @@
+package rf95
+
+import (
+	"sync"
+	"time"
+)
+
+// histogramBounds are the upper bounds of a Histogram's buckets, doubling from 100 µs to about 54 s.
+var histogramBounds = func() []time.Duration {
+	bounds := make([]time.Duration, 20)
+	for i := range bounds {
+		bounds[i] = 100 * time.Microsecond << i
+	}
+	return bounds
+}()
+
+// Histogram is a distribution of latencies in exponentially growing buckets.
+type Histogram struct {
+	// Bounds are the buckets' inclusive upper bounds. Counts has one more
+	// entry for latencies exceeding the last bound.
+	Bounds []time.Duration
+	Counts []uint64
+
+	// Count and Sum of all recorded latencies.
+	Count uint64
+	Sum   time.Duration
+}
+
+// newHistogram without any recorded latencies.
+func newHistogram() *Histogram {
+	return &Histogram{
+		Bounds: histogramBounds,
+		Counts: make([]uint64, len(histogramBounds)+1),
+	}
+}
+
+// record a latency.
+func (h *Histogram) record(d time.Duration) {
+	i := 0
+	for i < len(h.Bounds) && d > h.Bounds[i] {
+		i++
+	}
+
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// clone creates a deep copy.
+func (h *Histogram) clone() Histogram {
+	c := *h
+	c.Counts = append([]uint64(nil), h.Counts...)
+	return c
+}
+
+// Mean of all recorded latencies.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile estimates the q-quantile, q in [0, 1], by the upper bound of its bucket.
+//
+// Latencies beyond the last bound are estimated by the last bound.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(h.Count))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, count := range h.Counts {
+		if seen += count; seen >= rank && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+// Latencies are the latency distributions of a Modem, acquired by Modem.Latencies.
+type Latencies struct {
+	// Queue is the time AT commands waited for previous commands to finish
+	// before being written to the rf95modem.
+	Queue Histogram
+
+	// Response is the time from writing an AT command until its complete
+	// response, per AT command name, e.g., AT+TX until +SENT.
+	Response map[string]Histogram
+
+	// Dispatch is the time from reading an RX line until all RX handlers returned.
+	Dispatch Histogram
+}
+
+// latencies records a Modem's Latencies.
+type latencies struct {
+	queue    *Histogram
+	response map[string]*Histogram
+	dispatch *Histogram
+
+	mutex sync.Mutex
+}
+
+func newLatencies() *latencies {
+	return &latencies{
+		queue:    newHistogram(),
+		response: make(map[string]*Histogram),
+		dispatch: newHistogram(),
+	}
+}
+
+func (l *latencies) recordQueue(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.queue.record(d)
+}
+
+func (l *latencies) recordResponse(cmd string, d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	name := atCommandName(cmd)
+	if l.response[name] == nil {
+		l.response[name] = newHistogram()
+	}
+	l.response[name].record(d)
+}
+
+func (l *latencies) recordDispatch(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.dispatch.record(d)
+}
+
+// snapshot of the recorded Latencies.
+func (l *latencies) snapshot() Latencies {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	snapshot := Latencies{
+		Queue:    l.queue.clone(),
+		Response: make(map[string]Histogram, len(l.response)),
+		Dispatch: l.dispatch.clone(),
+	}
+	for name, h := range l.response {
+		snapshot.Response[name] = h.clone()
+	}
+	return snapshot
+}
+
+// Latencies returns the latency distributions since the Modem's creation.
+func (modem *Modem) Latencies() Latencies {
+	return modem.latencies.snapshot()
+}
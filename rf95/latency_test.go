@@ -0,0 +1,59 @@
+package rf95
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram()
+	for _, d := range []time.Duration{50 * time.Microsecond, 150 * time.Microsecond, 150 * time.Microsecond, time.Hour} {
+		h.record(d)
+	}
+
+	if h.Count != 4 {
+		t.Fatalf("histogram counts %d latencies, expected 4", h.Count)
+	} else if h.Counts[0] != 1 || h.Counts[1] != 2 || h.Counts[len(h.Counts)-1] != 1 {
+		t.Fatalf("histogram has buckets %v", h.Counts)
+	}
+
+	tests := []struct {
+		q        float64
+		quantile time.Duration
+	}{
+		{0, 100 * time.Microsecond},
+		{0.5, 200 * time.Microsecond},
+		{1, histogramBounds[len(histogramBounds)-1]},
+	}
+
+	for _, test := range tests {
+		if quantile := h.Quantile(test.q); quantile != test.quantile {
+			t.Fatalf("%.2f-quantile is %v, expected %v", test.q, quantile, test.quantile)
+		}
+	}
+}
+
+func TestModemLatencies(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	rxChan := make(chan RxMessage, 1)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dev.inject("+RX 3,414141,-15,8\n")
+	<-rxChan
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+
+	latencies := modem.Latencies()
+	if latencies.Queue.Count != 2 {
+		t.Fatalf("queue latencies count %d commands, expected 2", latencies.Queue.Count)
+	} else if latencies.Response["AT+INFO"].Count != 2 {
+		t.Fatalf("response latencies count %d AT+INFO commands, expected 2", latencies.Response["AT+INFO"].Count)
+	} else if latencies.Dispatch.Count != 1 {
+		t.Fatalf("dispatch latencies count %d messages, expected 1", latencies.Dispatch.Count)
+	}
+}
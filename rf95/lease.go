@@ -0,0 +1,84 @@
+package rf95
+
+import (
+	"errors"
+)
+
+// ErrConfigLeased is returned for configuration changes while another party holds the ConfigLease.
+var ErrConfigLeased = errors.New("modem configuration is leased")
+
+// ConfigLease grants exclusive access to a Modem's configuration.
+//
+// When a Modem is shared, e.g., between multiple clients, only the holder of
+// the ConfigLease might change the mode or frequency. All other changes fail
+// with ErrConfigLeased until the lease is released.
+type ConfigLease struct {
+	modem *Modem
+}
+
+// AcquireConfigLease for exclusive configuration access, failing with ErrConfigLeased if already held.
+func (modem *Modem) AcquireConfigLease() (*ConfigLease, error) {
+	modem.leaseMutex.Lock()
+	defer modem.leaseMutex.Unlock()
+
+	if modem.lease != nil {
+		return nil, ErrConfigLeased
+	}
+
+	modem.lease = &ConfigLease{modem: modem}
+	return modem.lease, nil
+}
+
+// withLease executes the configuration change fn if no other lease than the given one is held.
+//
+// The lease's state is locked during fn. Thus, no lease can be acquired while
+// an unleased change is in progress.
+func (modem *Modem) withLease(lease *ConfigLease, fn func() error) error {
+	modem.leaseMutex.Lock()
+	defer modem.leaseMutex.Unlock()
+
+	if modem.lease != lease {
+		return ErrConfigLeased
+	}
+	return fn()
+}
+
+// Release the ConfigLease. Afterwards, its methods fail with ErrConfigLeased.
+func (lease *ConfigLease) Release() {
+	lease.modem.leaseMutex.Lock()
+	defer lease.modem.leaseMutex.Unlock()
+
+	if lease.modem.lease == lease {
+		lease.modem.lease = nil
+	}
+}
+
+// Mode sets the ModemMode, see Modem.Mode.
+func (lease *ConfigLease) Mode(mode ModemMode) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.mode(mode) })
+}
+
+// Frequency changes the frequency specified in MHz, see Modem.Frequency.
+func (lease *ConfigLease) Frequency(frequency float64) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.frequency(frequency) })
+}
+
+// SetBfb switches big BLE frames on or off, see Modem.SetBfb.
+func (lease *ConfigLease) SetBfb(enabled bool) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.setBfb(enabled) })
+}
+
+// SetReceive switches the RX listener on or off, see Modem.SetReceive.
+func (lease *ConfigLease) SetReceive(enabled bool) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.setReceive(enabled) })
+}
+
+// TxPower sets the transmit power in dBm, see Modem.TxPower.
+func (lease *ConfigLease) TxPower(dbm int) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.txPower(dbm) })
+}
+
+// Configure both the frequency in MHz and the ModemMode at once, see Modem.Configure.
+func (lease *ConfigLease) Configure(frequency float64, mode ModemMode) error {
+	return lease.modem.withLease(lease, func() error { return lease.modem.configure(frequency, mode) })
+}
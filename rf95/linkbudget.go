@@ -0,0 +1,100 @@
+package rf95
+
+import (
+	"math"
+)
+
+// Position is a geographic position in decimal degrees.
+type Position struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// earthRadius is the mean radius of the earth in meters.
+const earthRadius = 6371008.8
+
+// Distance to another Position in meters, calculated by the haversine formula.
+func (pos Position) Distance(other Position) float64 {
+	lat1, lat2 := pos.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - pos.Longitude) * math.Pi / 180
+
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// snrLimits are the demodulator's SNR limits in dB per spreading factor, taken from the SX1276 datasheet.
+var snrLimits = map[int]float64{
+	6:  -5,
+	7:  -7.5,
+	8:  -10,
+	9:  -12.5,
+	10: -15,
+	11: -17.5,
+	12: -20,
+}
+
+// receiverNoiseFigure of the SX1276 in dB.
+const receiverNoiseFigure = 6
+
+// Sensitivity estimates the receiver's sensitivity in dBm for this ModemMode.
+//
+// The estimation is based on the thermal noise floor, the receiver's noise
+// figure and the spreading factor's SNR limit. An unknown ModemMode results in
+// zero.
+func (mode ModemMode) Sensitivity() float64 {
+	params, ok := modemModes[mode]
+	if !ok {
+		return 0
+	}
+
+	return -174 + 10*math.Log10(params.bandwidth) + receiverNoiseFigure + snrLimits[params.spreading]
+}
+
+// LinkBudget describes the parameters of a radio link between two stations.
+type LinkBudget struct {
+	// Transmitter and Receiver are the stations' positions.
+	Transmitter Position
+	Receiver    Position
+
+	// TxPower is the transmitter's output power in dBm.
+	TxPower float64
+
+	// TxGain and RxGain are the antenna gains in dBi, reduced by cable losses.
+	TxGain float64
+	RxGain float64
+
+	// Frequency in MHz.
+	Frequency float64
+
+	// Mode of both rf95modems.
+	Mode ModemMode
+}
+
+// LinkReport are the engineering numbers for a received packet, created by LinkBudget.Evaluate.
+type LinkReport struct {
+	// Distance between both stations in meters.
+	Distance float64
+
+	// PathLoss is the measured loss between both antennas in dB.
+	PathLoss float64
+
+	// FreeSpaceLoss is the theoretical path loss in free space in dB.
+	FreeSpaceLoss float64
+
+	// FadeMargin is the reserve of the received signal above the receiver's sensitivity in dB.
+	FadeMargin float64
+}
+
+// Evaluate the LinkBudget for a received packet.
+func (budget LinkBudget) Evaluate(rx RxMessage) (report LinkReport) {
+	report.Distance = budget.Transmitter.Distance(budget.Receiver)
+	report.PathLoss = budget.TxPower + budget.TxGain + budget.RxGain - float64(rx.Rssi)
+
+	if report.Distance > 0 {
+		report.FreeSpaceLoss = 20*math.Log10(report.Distance/1000) + 20*math.Log10(budget.Frequency) + 32.44
+	}
+
+	report.FadeMargin = float64(rx.Rssi) - budget.Mode.Sensitivity()
+	return
+}
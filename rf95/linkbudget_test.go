@@ -0,0 +1,37 @@
+package rf95
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLinkBudgetEvaluate(t *testing.T) {
+	budget := LinkBudget{
+		Transmitter: Position{Latitude: 0, Longitude: 0},
+		Receiver:    Position{Latitude: 0, Longitude: 1},
+		TxPower:     14,
+		TxGain:      2,
+		RxGain:      3,
+		Frequency:   868.1,
+		Mode:        MediumRange,
+	}
+
+	report := budget.Evaluate(RxMessage{Rssi: -110, Snr: 5})
+
+	tests := []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{"distance", report.Distance, 111195.08},
+		{"path loss", report.PathLoss, 129},
+		{"free space loss", report.FreeSpaceLoss, 132.13},
+		{"fade margin", report.FadeMargin, 14.53},
+	}
+
+	for _, test := range tests {
+		if math.Abs(test.value-test.expected) > 0.01 {
+			t.Fatalf("%s is %f, expected %f", test.name, test.value, test.expected)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rf95
+
+import (
+	"time"
+)
+
+// LinkStats describes the usage of the link between host and rf95modem, acquired by Modem.LinkStats.
+//
+// At high packet rates, the hex encoded serial link rather than the radio might
+// become the bottleneck. Comparing WriteTime and WaitTime makes this visible.
+type LinkStats struct {
+	// Since is the start of the accounting, the Modem's creation.
+	Since time.Time
+
+	// Commands is the number of executed AT commands.
+	Commands int
+
+	// BytesWritten and BytesRead over the link.
+	BytesWritten int
+	BytesRead    int
+
+	// WriteTime is spent writing commands to the link. For links with a known
+	// baud rate, this is at least the time the bytes need on the wire.
+	WriteTime time.Duration
+
+	// WaitTime is spent waiting for the rf95modem's responses, e.g., while a
+	// packet is being transmitted.
+	WaitTime time.Duration
+
+	// RxCorrupted is the number of dropped RX lines which were malformed or
+	// whose payload did not match their length field, e.g., truncated lines.
+	RxCorrupted int
+}
+
+// Utilization is the share of time, in [0, 1], the link was busy with AT commands.
+func (stats LinkStats) Utilization() float64 {
+	elapsed := time.Since(stats.Since)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(stats.WriteTime+stats.WaitTime) / float64(elapsed)
+}
+
+// WriteShare is the share of time, in [0, 1], AT commands spent writing instead of waiting.
+func (stats LinkStats) WriteShare() float64 {
+	busy := stats.WriteTime + stats.WaitTime
+	if busy <= 0 {
+		return 0
+	}
+	return float64(stats.WriteTime) / float64(busy)
+}
+
+// wireTime is the time n bytes need on a serial link with ten bits per byte.
+func wireTime(n, baud int) time.Duration {
+	if baud <= 0 {
+		return 0
+	}
+	return time.Duration(n*10) * time.Second / time.Duration(baud)
+}
@@ -0,0 +1,99 @@
+package rf95
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WriteMetrics of the Modem in the Prometheus text exposition format.
+//
+// The metrics cover the LinkStats as counters and the Latencies as histograms,
+// the Response latencies being labeled by their AT command.
+func (modem *Modem) WriteMetrics(w io.Writer) error {
+	stats, lat := modem.LinkStats(), modem.Latencies()
+
+	var buff bytes.Buffer
+	writeCounter(&buff, "rf95_link_commands_total", "Executed AT commands.", float64(stats.Commands))
+	writeCounter(&buff, "rf95_link_written_bytes_total", "Bytes written to the rf95modem.", float64(stats.BytesWritten))
+	writeCounter(&buff, "rf95_link_read_bytes_total", "Bytes read from the rf95modem.", float64(stats.BytesRead))
+	writeCounter(&buff, "rf95_link_write_seconds_total", "Time spent writing AT commands.", seconds(stats.WriteTime))
+	writeCounter(&buff, "rf95_link_wait_seconds_total", "Time spent waiting for responses.", seconds(stats.WaitTime))
+	writeCounter(&buff, "rf95_rx_corrupted_total", "Dropped corrupted RX lines.", float64(stats.RxCorrupted))
+
+	writeHistogramHeader(&buff, "rf95_queue_latency_seconds", "Time AT commands waited for previous commands.")
+	writeHistogram(&buff, "rf95_queue_latency_seconds", "", lat.Queue)
+
+	writeHistogramHeader(&buff, "rf95_response_latency_seconds", "Time from writing an AT command until its complete response.")
+	names := make([]string, 0, len(lat.Response))
+	for name := range lat.Response {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeHistogram(&buff, "rf95_response_latency_seconds", fmt.Sprintf("command=%q", name), lat.Response[name])
+	}
+
+	writeHistogramHeader(&buff, "rf95_dispatch_latency_seconds", "Time from reading an RX line until all RX handlers returned.")
+	writeHistogram(&buff, "rf95_dispatch_latency_seconds", "", lat.Dispatch)
+
+	_, err := w.Write(buff.Bytes())
+	return err
+}
+
+// MetricsHandler serves WriteMetrics over HTTP, e.g., for a Prometheus scraper at /metrics.
+func (modem *Modem) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = modem.WriteMetrics(w)
+	})
+}
+
+// writeCounter with its HELP and TYPE lines.
+func writeCounter(buff *bytes.Buffer, name, help string, value float64) {
+	fmt.Fprintf(buff, "# HELP %s %s\n# TYPE %s counter\n%s %s\n", name, help, name, name, formatMetric(value))
+}
+
+// writeHistogramHeader with the HELP and TYPE lines of a histogram.
+func writeHistogramHeader(buff *bytes.Buffer, name, help string) {
+	fmt.Fprintf(buff, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+}
+
+// writeHistogram as cumulative buckets with its sum and count; labels might be empty.
+func writeHistogram(buff *bytes.Buffer, name, labels string, h Histogram) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+
+	var cumulative uint64
+	for i, count := range h.Counts {
+		cumulative += count
+
+		le := "+Inf"
+		if i < len(h.Bounds) {
+			le = formatMetric(seconds(h.Bounds[i]))
+		}
+		fmt.Fprintf(buff, "%s_bucket{%s%sle=%q} %d\n", name, labels, sep, le, cumulative)
+	}
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(buff, "%s_sum%s %s\n", name, labels, formatMetric(seconds(h.Sum)))
+	fmt.Fprintf(buff, "%s_count%s %d\n", name, labels, h.Count)
+}
+
+// seconds of a Duration, without the rounding errors of Duration.Seconds for the bucket bounds.
+func seconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Second)
+}
+
+// formatMetric as the shortest float representation.
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
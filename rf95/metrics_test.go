@@ -0,0 +1,33 @@
+package rf95
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestModemMetrics(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	modem.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	metrics := recorder.Body.String()
+
+	for _, expected := range []string{
+		"# TYPE rf95_link_commands_total counter\nrf95_link_commands_total 1\n",
+		"rf95_rx_corrupted_total 0\n",
+		"# TYPE rf95_response_latency_seconds histogram\n",
+		"rf95_response_latency_seconds_bucket{command=\"AT+INFO\",le=\"+Inf\"} 1\n",
+		"rf95_response_latency_seconds_count{command=\"AT+INFO\"} 1\n",
+		"rf95_queue_latency_seconds_bucket{le=\"0.0001\"} ",
+		"rf95_dispatch_latency_seconds_count 0\n",
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Fatalf("metrics lack %q:\n%s", expected, metrics)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package rf95
+
+// RxHandler processes a received RxMessage.
+type RxHandler func(RxMessage)
+
+// RxMiddleware wraps the next RxHandler of the RX pipeline.
+//
+// A middleware might inspect, alter, or drop an RxMessage before passing it on
+// to next, e.g., for de-duplication, decryption, metrics, or filtering. The
+// next RxHandler must be called synchronously, if at all.
+type RxMiddleware func(next RxHandler) RxHandler
+
+// Use appends middlewares to the Modem's RX pipeline.
+//
+// Each received RxMessage passes the middlewares in the order of their
+// addition, the first being the outermost, before being dispatched to all RX
+// handlers, ordered as described for RegisterRxHandler.
+func (modem *Modem) Use(middlewares ...RxMiddleware) {
+	modem.handlerMutex.Lock()
+	defer modem.handlerMutex.Unlock()
+
+	modem.rxMiddlewares = append(modem.rxMiddlewares, middlewares...)
+
+	modem.rxChain = modem.dispatchRx
+	for i := len(modem.rxMiddlewares) - 1; i >= 0; i-- {
+		modem.rxChain = modem.rxMiddlewares[i](modem.rxChain)
+	}
+}
+
+// dispatchRx passes an RxMessage to all RX handlers; handlerMutex must be held.
+func (modem *Modem) dispatchRx(rxMsg RxMessage) {
+	for _, rxHandler := range modem.rxHandlers {
+		rxHandler.handle(rxMsg)
+	}
+}
+
+// TxHandler transmits a byte array and returns how many of its bytes were sent.
+type TxHandler func(p []byte) (int, error)
+
+// TxMiddleware wraps the next TxHandler of the TX pipeline.
+//
+// A middleware might transform a byte array before passing it on to next,
+// e.g., by compression, encryption, or a proprietary header, or reject it with
+// an error, e.g., to enforce a duty cycle. It must return the number of bytes
+// of its own input which were sent, e.g., len(p) after next succeeded with a
+// transformed byte array. As Transmit might be called concurrently, so might
+// each middleware. A middleware enlarging the byte array must reserve its
+// overhead by SoftMtu, such that a Stream's fragments still fit.
+type TxMiddleware func(next TxHandler) TxHandler
+
+// UseTx appends middlewares to the Modem's TX pipeline.
+//
+// Each byte array passed to Transmit, e.g., by a Stream, passes the
+// middlewares in the order of their addition, the first being the outermost,
+// before being sent by AT+TX.
+func (modem *Modem) UseTx(middlewares ...TxMiddleware) {
+	modem.handlerMutex.Lock()
+	defer modem.handlerMutex.Unlock()
+
+	modem.txMiddlewares = append(modem.txMiddlewares, middlewares...)
+
+	modem.txChain = modem.transmit
+	for i := len(modem.txMiddlewares) - 1; i >= 0; i-- {
+		modem.txChain = modem.txMiddlewares[i](modem.txChain)
+	}
+}
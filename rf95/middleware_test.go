@@ -0,0 +1,89 @@
+package rf95
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModemUse(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	var order []string
+	trace := func(name string) RxMiddleware {
+		return func(next RxHandler) RxHandler {
+			return func(msg RxMessage) {
+				order = append(order, name)
+				next(msg)
+			}
+		}
+	}
+	weakFilter := func(next RxHandler) RxHandler {
+		return func(msg RxMessage) {
+			if msg.Rssi >= -100 {
+				next(msg)
+			}
+		}
+	}
+
+	rxMsgs := make(chan RxMessage, 2)
+	if _, err := modem.RegisterHandlers(func(msg RxMessage) { rxMsgs <- msg }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	modem.Use(trace("outer"), weakFilter)
+	modem.Use(trace("inner"))
+
+	dev.inject("+RX 2,ACAB,-120,8\n", "+RX 2,ACAB,-15,8\n")
+
+	if msg := <-rxMsgs; msg.Rssi != -15 {
+		t.Fatalf("received packet with RSSI %d, expected the weak one to be filtered", msg.Rssi)
+	}
+	if expected := []string{"outer", "outer", "inner"}; !reflect.DeepEqual(order, expected) {
+		t.Fatalf("middlewares were executed as %v, expected %v", order, expected)
+	}
+}
+
+func TestModemUseTx(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+TX=") {
+			cmds = append(cmds, cmd)
+			return []string{fmt.Sprintf("+SENT %d bytes.\n", len(cmd[len("AT+TX="):])/2)}
+		}
+		return nil
+	})
+
+	header := func(next TxHandler) TxHandler {
+		return func(p []byte) (int, error) {
+			if _, err := next(append([]byte{0x42}, p...)); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	rejectEmpty := func(next TxHandler) TxHandler {
+		return func(p []byte) (int, error) {
+			if len(p) == 0 {
+				return 0, fmt.Errorf("empty payload")
+			}
+			return next(p)
+		}
+	}
+
+	modem.UseTx(rejectEmpty, header)
+
+	if n, err := modem.Transmit([]byte{0xAC, 0xAB}); err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("transmitted %d bytes, expected 2", n)
+	}
+	if _, err := modem.Transmit(nil); err == nil {
+		t.Fatal("transmitting an empty payload was not rejected")
+	}
+
+	if expected := []string{"AT+TX=42acab"}; !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("sent %v, expected %v", cmds, expected)
+	}
+}
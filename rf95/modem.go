@@ -5,15 +5,15 @@ import (
 	"bufio"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/tarm/serial"
 )
 
 // ModemMode is the rf95modem's config mode, specified by AT+MODE.
@@ -39,135 +39,358 @@ const (
 	maxModemMode = int(SlowLongRange3)
 )
 
+const (
+	// minFrequency is the lower bound of the SX1276's tuning range in MHz.
+	minFrequency = 137.0
+
+	// maxFrequency is the upper bound of the SX1276's tuning range in MHz.
+	maxFrequency = 1020.0
+)
+
 // RxMessage represents a received message with its fields.
 type RxMessage struct {
 	Payload []byte
 	Rssi    int
 	Snr     int
+
+	// Time is the wall clock time of the reception, which might drift or jump.
+	// Use ClockCorrection to correct it afterwards.
+	Time time.Time
+
+	// Monotonic is the reception time relative to the Modem's creation,
+	// measured by the monotonic clock and thus unaffected by clock changes.
+	Monotonic time.Duration
+
+	// Fix of the receiver at the reception, if a PositionSource is set and
+	// knows it; nil otherwise.
+	Fix *Fix
 }
 
 // Status describes the rf95modem's status, acquired by AT+INFO.
 type Status struct {
 	Firmware  string
-	Features  []string
+	Features  []Feature
 	Mode      ModemMode
 	Mtu       int
 	Frequency float64
 	Bfb       int
+	TxPower   int
+	Receive   bool
 	RxBad     int
 	RxGood    int
 	TxGood    int
 }
 
+// ConfigReport describes the outcome of a configuration change, acquired by DryRun.
+type ConfigReport struct {
+	// Current is the rf95modem's Status before the change.
+	Current Status
+
+	// Mode and Frequency after the change.
+	Mode      ModemMode
+	Frequency float64
+
+	// TxPower after the change, zero if unchanged, see DryRunState.
+	TxPower int
+
+	// CurrentAirtime and Airtime are the time on air of a packet of the MTU's
+	// size before and after the change.
+	CurrentAirtime time.Duration
+	Airtime        time.Duration
+
+	// Violations are the reasons the change would fail or break regulations,
+	// e.g., an unsupported ModemMode. The change is valid if there are none.
+	Violations []error
+}
+
 // Modem manages the connection to a rf95modem.
 //
 // After creation, it's state can be fetched or altered. New handler can be
 // registered for data reception and raw data can be send.
 type Modem struct {
+	created time.Time
+
 	devReader io.Reader
 	devWriter io.Writer
 	devCloser io.Closer
-
-	rxHandlers   []func(RxMessage)
-	mtuHandlers  []func(int)
-	handlerMutex sync.RWMutex
+	devClosed bool
+	devLost   chan struct{}
+	devMutex  sync.Mutex
+
+	// baud of a serial link and retryEOF, see ModemConfig, are protected by the devMutex.
+	baud     int
+	retryEOF bool
+
+	swapReader      io.Reader
+	swapDone        chan struct{}
+	transport       Transport
+	reconnectPolicy *ReconnectPolicy
+	knownState      *State
+
+	rxHandlers     []prioritizedHandler
+	rxMiddlewares  []RxMiddleware
+	rxChain        RxHandler
+	txMiddlewares  []TxMiddleware
+	txChain        TxHandler
+	mtuHandlers    []func(int)
+	positionSource PositionSource
+	handlerMutex   sync.RWMutex
+
+	firmwareMtu int
+	softMtu     int
+	mtuMutex    sync.Mutex
 
 	atCommandMutex sync.Mutex
 	msgQueue       chan string
 
+	commands      []Command
+	commandsMutex sync.Mutex
+
+	lease      *ConfigLease
+	leaseMutex sync.Mutex
+
+	// cmdPending counts callers waiting for the atCommandMutex and is protected
+	// through sync/atomic calls.
+	cmdPending int32
+	bgQueue    chan func()
+
+	// frequencyDigits is the precision of AT+FREQ and dialect the Dialect of
+	// responses, both accessed through sync/atomic calls.
+	frequencyDigits int32
+	dialect         int32
+
+	frequencyCorrection frequencyCorrection
+	frequencyMutex      sync.Mutex
+
+	transcript *transcript
+	latencies  *latencies
+
+	linkStats      LinkStats
+	linkStatsMutex sync.Mutex
+
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 }
 
+// ModemConfig describes a Modem for OpenModemConfig and OpenTransportConfig.
+type ModemConfig struct {
+	// Profile sizes the Modem's buffers, e.g., TinyProfile for memory-constrained
+	// gateways. The zero Profile selects the StandardProfile.
+	Profile Profile
+
+	// Baud rate of a serial link, estimating the time on the wire for the
+	// LinkStats. Zero for other links.
+	Baud int
+
+	// RetryEOF keeps reading after an io.EOF, for readers reporting read
+	// timeouts as EOF, e.g., some serial drivers. Otherwise, an EOF finishes a
+	// Modem without a ReconnectPolicy, as for any other read error.
+	RetryEOF bool
+}
+
 // OpenModem creates a new Modem backed by some stream.
 //
 // Both the io.Reader as well as the io.Writer are necessary. The io.Closer
-// might be nil. The Modem finishes when the Context is done.
-func OpenModem(r io.Reader, w io.Writer, c io.Closer, ctx context.Context) (modem *Modem, err error) {
+// might be nil. The Modem finishes when the Context is done or, without a
+// ReconnectPolicy, when reading from the device fails, e.g., by an EOF after
+// the peer closed the connection. For readers reporting read timeouts as EOF,
+// use OpenModemConfig with RetryEOF. Its buffers are sized by the
+// StandardProfile.
+func OpenModem(r io.Reader, w io.Writer, c io.Closer, ctx context.Context) (*Modem, error) {
+	return OpenModemConfig(r, w, c, ModemConfig{}, ctx)
+}
+
+// OpenModemConfig creates a new Modem backed by some stream, configured by the ModemConfig.
+//
+// For the stream and Context information, check OpenModem's documentation.
+func OpenModemConfig(r io.Reader, w io.Writer, c io.Closer, config ModemConfig, ctx context.Context) (modem *Modem, err error) {
+	profile := config.Profile.orStandard()
+
 	modem = &Modem{
-		devReader: r,
-		devWriter: w,
-		devCloser: c,
-		msgQueue:  make(chan string, 128),
+		created:    time.Now(),
+		devReader:  r,
+		devWriter:  w,
+		devCloser:  c,
+		devLost:    make(chan struct{}),
+		msgQueue:   make(chan string, profile.MsgQueue),
+		bgQueue:    make(chan func(), profile.BackgroundQueue),
+		transcript: newTranscript(profile.TranscriptLines),
+		latencies:  newLatencies(),
+		linkStats:  LinkStats{Since: time.Now()},
+		baud:       config.Baud,
+		retryEOF:   config.RetryEOF,
+
+		frequencyDigits: defaultFrequencyDigits,
 	}
 
 	modem.ctx, modem.ctxCancel = context.WithCancel(ctx)
 
-	go modem.worker()
+	go modem.worker(r)
+	go modem.backgroundWorker()
+
+	// Close the device as soon as the Modem is finished. Otherwise, a blocking
+	// read, e.g., on a network connection, would keep the worker waiting.
+	go func() {
+		<-modem.ctx.Done()
+		modem.closeDevice()
+	}()
 
 	return
 }
 
+// closeDevice closes the Closer if not nil, exactly once.
+func (modem *Modem) closeDevice() {
+	modem.devMutex.Lock()
+	defer modem.devMutex.Unlock()
+
+	if !modem.devClosed && modem.devCloser != nil {
+		_ = modem.devCloser.Close()
+	}
+	modem.devClosed = true
+}
+
 // OpenSerial creates a new Modem based on a serial connection to a rf95modem.
 //
 // The device parameter might be /dev/ttyUSB0, or your operating system's
 // equivalent. For Context information, check OpenModem's documentation.
-func OpenSerial(device string, ctx context.Context) (modem *Modem, err error) {
-	serialConf := &serial.Config{
-		Name:        device,
-		Baud:        115200,
-		ReadTimeout: time.Second,
-	}
+func OpenSerial(device string, ctx context.Context) (*Modem, error) {
+	return OpenSerialConfig(device, DefaultSerialConfig, ctx)
+}
 
-	serialPort, serialPortErr := serial.OpenPort(serialConf)
-	if serialPortErr != nil {
-		err = serialPortErr
-		return
-	}
+// rxRegexp matches an RX message, compiled once for the hot path.
+var rxRegexp = regexp.MustCompile(`^\+RX (\d+),([0-9A-Fa-f]+),([-0-9]+),([-0-9]+)\r?\n$`)
 
-	return OpenModem(serialPort, serialPort, serialPort, ctx)
-}
+// ErrRxCorrupted is returned for RX messages whose payload does not match their length field, e.g., truncated lines.
+var ErrRxCorrupted = errors.New("received packet is corrupted")
 
 // parsePacketRx tries to extract the fields of an RX message.
+//
+// The payload's length is cross-checked against the length field.
 func parsePacketRx(msg string) (rx RxMessage, err error) {
-	rxRegexp := regexp.MustCompile(`^\+RX \d+,([0-9A-Fa-f]+),([-0-9]+),([-0-9]+)\r?\n$`)
 	findings := rxRegexp.FindStringSubmatch(msg)
-	if len(findings) != 4 {
+	if len(findings) != 5 {
 		err = fmt.Errorf("found no matching RX fields")
 		return
 	}
 
-	if rx.Payload, err = hex.DecodeString(findings[1]); err != nil {
+	var length int
+	if length, err = strconv.Atoi(findings[1]); err != nil {
 		return
-	} else if rx.Rssi, err = strconv.Atoi(findings[2]); err != nil {
+	} else if rx.Payload, err = hex.DecodeString(findings[2]); err != nil {
 		return
-	} else if rx.Snr, err = strconv.Atoi(findings[3]); err != nil {
+	} else if rx.Rssi, err = strconv.Atoi(findings[3]); err != nil {
+		return
+	} else if rx.Snr, err = strconv.Atoi(findings[4]); err != nil {
 		return
 	}
 
+	if len(rx.Payload) != length {
+		err = fmt.Errorf("%w: length field is %d, payload has %d bytes", ErrRxCorrupted, length, len(rx.Payload))
+		rx = RxMessage{}
+	}
 	return
 }
 
-// worker reads the input stream and runs within a Goroutine after OpenModem.
+// worker reads the initial devReader and its replacements and runs within a
+// Goroutine after OpenModem.
 //
 // Received data will either be distributed to all RX handlers or added to the
 // msgQueue when needed for other tasks.
-func (modem *Modem) worker() {
-	var reader = bufio.NewReader(modem.devReader)
+// RX messages fragmented across multiple lines are reassembled first.
+func (modem *Modem) worker(devReader io.Reader) {
+	var reader = bufio.NewReader(devReader)
+	var assembler rxAssembler
 
 	for {
 		select {
 		case <-modem.ctx.Done():
-			if modem.devCloser != nil {
-				_ = modem.devCloser.Close()
-			}
+			modem.closeDevice()
 			return
 
 		default:
 			lineMsg, lineErr := reader.ReadString('\n')
-			if lineErr == io.EOF {
-				continue
-			} else if lineErr != nil {
+			lineTime := time.Now()
+			if lineErr != nil && modem.ctx.Err() == nil {
+				modem.devMutex.Lock()
+				policy := modem.reconnectPolicy
+				swapReader, swapDone := modem.swapReader, modem.swapDone
+				modem.swapReader, modem.swapDone = nil, nil
+				modem.devMutex.Unlock()
+
+				if swapReader != nil {
+					reader = bufio.NewReader(swapReader)
+					assembler.reset()
+					close(swapDone)
+					continue
+				} else if policy != nil {
+					devReader, ok := modem.reconnect(lineErr, policy)
+					if !ok {
+						_ = modem.Close()
+						return
+					}
+
+					reader = bufio.NewReader(devReader)
+					assembler.reset()
+					continue
+				}
+			}
+
+			if lineErr != nil {
+				modem.devMutex.Lock()
+				retryEOF := modem.retryEOF
+				modem.devMutex.Unlock()
+
+				if lineErr == io.EOF && retryEOF {
+					continue
+				}
+
+				modem.loseDevice()
+				_ = modem.Close()
 				return
 			}
 
+			modem.transcript.record(Received, lineMsg)
+
+			modem.linkStatsMutex.Lock()
+			modem.linkStats.BytesRead += len(lineMsg)
+			modem.linkStatsMutex.Unlock()
+
+			if assembler.pending() {
+				switch msg, state := assembler.feed(lineMsg); state {
+				case rxFragmentPending:
+					continue
+				case rxFragmentComplete:
+					lineMsg = msg
+				case rxFragmentAborted:
+					modem.linkStatsMutex.Lock()
+					modem.linkStats.RxCorrupted++
+					modem.linkStatsMutex.Unlock()
+				}
+			}
+
 			if strings.HasPrefix(lineMsg, "+RX") {
 				if rxMsg, rxErr := parsePacketRx(lineMsg); rxErr == nil {
+					rxMsg.Time = lineTime
+					rxMsg.Monotonic = lineTime.Sub(modem.created)
+
 					modem.handlerMutex.RLock()
-					for _, rxHandler := range modem.rxHandlers {
-						rxHandler(rxMsg)
+					if modem.positionSource != nil {
+						if fix, ok := modem.positionSource.Fix(); ok {
+							rxMsg.Fix = &fix
+						}
+					}
+					if modem.rxChain != nil {
+						modem.rxChain(rxMsg)
+					} else {
+						modem.dispatchRx(rxMsg)
 					}
 					modem.handlerMutex.RUnlock()
+
+					modem.latencies.recordDispatch(time.Since(lineTime))
+				} else if !assembler.begin(lineMsg) {
+					modem.linkStatsMutex.Lock()
+					modem.linkStats.RxCorrupted++
+					modem.linkStatsMutex.Unlock()
 				}
 			} else {
 				modem.msgQueue <- lineMsg
@@ -176,12 +399,26 @@ func (modem *Modem) worker() {
 	}
 }
 
+// loseDevice signals pending AT commands that the device is lost for good.
+func (modem *Modem) loseDevice() {
+	modem.devMutex.Lock()
+	defer modem.devMutex.Unlock()
+
+	select {
+	case <-modem.devLost:
+	default:
+		close(modem.devLost)
+	}
+}
+
 // Close down the internal worker and Closer if not nil.
 func (modem *Modem) Close() (err error) {
 	modem.ctxCancel()
 
 	modem.handlerMutex.Lock()
 	modem.rxHandlers = nil
+	modem.rxMiddlewares = nil
+	modem.rxChain = nil
 	modem.mtuHandlers = nil
 	modem.handlerMutex.Unlock()
 
@@ -190,12 +427,13 @@ func (modem *Modem) Close() (err error) {
 
 // RegisterHandlers for RxMessages and MTU updates.
 //
-// Each handler might be nil and thus won't be registered. The returned Context
+// Each handler might be nil and thus won't be registered. RX handlers are
+// registered with PriorityDefault, see RegisterRxHandler. The returned Context
 // will be done if the Modem is finished.
 func (modem *Modem) RegisterHandlers(rxHandler func(RxMessage), mtuHandler func(int)) (context.Context, error) {
 	modem.handlerMutex.Lock()
 	if rxHandler != nil {
-		modem.rxHandlers = append(modem.rxHandlers, rxHandler)
+		modem.addRxHandler(rxHandler, PriorityDefault)
 	}
 	if mtuHandler != nil {
 		modem.mtuHandlers = append(modem.mtuHandlers, mtuHandler)
@@ -210,58 +448,119 @@ func (modem *Modem) RegisterHandlers(rxHandler func(RxMessage), mtuHandler func(
 	return modem.ctx, nil
 }
 
-// atCommand executes an AT command and reads lines until stopFn returns false.
+// atRequest is an AT command together with its stopFn, see atCommand.
+type atRequest struct {
+	cmd    string
+	stopFn func(string) bool
+}
+
+// atPipeline executes multiple AT commands by writing them at once and reading
+// back their responses in order, as specified by each stopFn.
 //
-// The last line where stopFn returns false will also be included in lines.
-func (modem *Modem) atCommand(cmd string, stopFn func(string) bool) (lines []string, err error) {
+// This saves round trips on high-latency links. However, it is only safe for
+// short commands as the rf95modem's serial input buffer is limited.
+func (modem *Modem) atPipeline(reqs []atRequest) (responses [][]string, err error) {
+	queueStart := time.Now()
+	atomic.AddInt32(&modem.cmdPending, 1)
 	modem.atCommandMutex.Lock()
+	atomic.AddInt32(&modem.cmdPending, -1)
 	defer modem.atCommandMutex.Unlock()
 
-	_, err = modem.devWriter.Write([]byte(cmd + "\n"))
+	modem.latencies.recordQueue(time.Since(queueStart))
+
+	var cmds strings.Builder
+	for _, req := range reqs {
+		cmds.WriteString(req.cmd + "\n")
+		modem.transcript.record(Sent, req.cmd)
+	}
+
+	modem.devMutex.Lock()
+	devWriter, devLost, baud := modem.devWriter, modem.devLost, modem.baud
+	modem.devMutex.Unlock()
+
+	writeStart := time.Now()
+	n, err := devWriter.Write([]byte(cmds.String()))
+	writeEnd := time.Now()
+
+	defer func() {
+		writeTime := writeEnd.Sub(writeStart)
+		if wire := wireTime(n, baud); wire > writeTime {
+			writeTime = wire
+		}
+
+		waitTime := time.Since(writeStart) - writeTime
+		if waitTime < 0 {
+			waitTime = 0
+		}
+
+		modem.linkStatsMutex.Lock()
+		modem.linkStats.Commands += len(reqs)
+		modem.linkStats.BytesWritten += n
+		modem.linkStats.WriteTime += writeTime
+		modem.linkStats.WaitTime += waitTime
+		modem.linkStatsMutex.Unlock()
+	}()
+
 	if err != nil {
 		return
 	}
 
-	for {
-		select {
-		case <-modem.ctx.Done():
-			err = io.EOF
-			return
+	for _, req := range reqs {
+		var lines []string
+		for done := false; !done; {
+			select {
+			case <-modem.ctx.Done():
+				err = io.EOF
+				return
 
-		case line := <-modem.msgQueue:
-			lines = append(lines, line)
-			if !stopFn(line) {
+			case <-devLost:
+				err = ErrConnectionLost
 				return
+
+			case line := <-modem.msgQueue:
+				lines = append(lines, line)
+				done = !req.stopFn(line)
 			}
 		}
+		responses = append(responses, lines)
+		modem.latencies.recordResponse(req.cmd, time.Since(writeEnd))
 	}
+	return
 }
 
-// atCommandOnce executes an AT command and reads back one line.
-func (modem *Modem) atCommandOnce(cmd string) (string, error) {
-	lines, err := modem.atCommand(cmd, func(string) bool { return false })
-	if err != nil {
-		return "", err
-	}
+// LinkStats returns the usage of the link to the rf95modem since its creation.
+func (modem *Modem) LinkStats() LinkStats {
+	modem.linkStatsMutex.Lock()
+	defer modem.linkStatsMutex.Unlock()
 
-	return lines[0], nil
+	return modem.linkStats
 }
 
 // Transmit the byte array whose length must be shorter than the Mtu.
 //
-// To transfer a byte array regardless of its length, create a Stream.
+// To transfer a byte array regardless of its length, create a Stream. The byte
+// array passes all TxMiddlewares first, see UseTx.
 func (modem *Modem) Transmit(p []byte) (int, error) {
-	respMsg, cmdErr := modem.atCommandOnce(fmt.Sprintf("AT+TX=%s", hex.EncodeToString(p)))
+	modem.handlerMutex.RLock()
+	txChain := modem.txChain
+	modem.handlerMutex.RUnlock()
+
+	if txChain != nil {
+		return txChain(p)
+	}
+	return modem.transmit(p)
+}
+
+// transmit the byte array by AT+TX, bypassing all TxMiddlewares.
+func (modem *Modem) transmit(p []byte) (int, error) {
+	cmd := fmt.Sprintf("AT+TX=%s", hex.EncodeToString(p))
+	results, cmdErr := modem.execute(cmd)
 	if cmdErr != nil {
 		return 0, cmdErr
 	}
 
-	respPattern := regexp.MustCompile(`^\+SENT (\d+) bytes\.\r?\n$`)
-	respMatch := respPattern.FindStringSubmatch(respMsg)
-	if len(respMatch) != 2 {
-		return 0, fmt.Errorf("unexpected response: %s", respMsg)
-	} else if n, nErr := strconv.Atoi(respMatch[1]); nErr != nil {
-		return 0, nErr
+	if n, nErr := strconv.Atoi(results[0].match[1]); nErr != nil {
+		return 0, modem.responseError(cmd, results[0].lines, nErr)
 	} else {
 		return n, nil
 	}
@@ -274,63 +573,214 @@ func (modem *Modem) refreshMtu() error {
 		return err
 	}
 
+	modem.mtuMutex.Lock()
+	modem.firmwareMtu = status.Mtu
+	modem.mtuMutex.Unlock()
+
+	modem.devMutex.Lock()
+	modem.knownState = &State{Mode: status.Mode, Frequency: status.Frequency, TxPower: status.TxPower}
+	modem.devMutex.Unlock()
+
+	modem.distributeMtu()
+	return nil
+}
+
+// distributeMtu passes the effective MTU to all MTU handlers.
+func (modem *Modem) distributeMtu() {
+	mtu := modem.Mtu()
+
 	modem.handlerMutex.RLock()
 	for _, mtuHandler := range modem.mtuHandlers {
-		mtuHandler(status.Mtu)
+		mtuHandler(mtu)
 	}
 	modem.handlerMutex.RUnlock()
+}
 
+// Mtu returns the effective MTU, the rf95modem's maximum packet size possibly
+// lowered by SoftMtu.
+func (modem *Modem) Mtu() int {
+	modem.mtuMutex.Lock()
+	defer modem.mtuMutex.Unlock()
+
+	if modem.softMtu > 0 && modem.softMtu < modem.firmwareMtu {
+		return modem.softMtu
+	}
+	return modem.firmwareMtu
+}
+
+// SoftMtu lowers the effective MTU below the rf95modem's maximum packet size.
+//
+// This leaves room for own headers or improves delivery in marginal conditions
+// by sending shorter packets. A value of zero removes the override. All MTU
+// handlers, e.g., of a Stream, are notified about the new effective MTU.
+func (modem *Modem) SoftMtu(mtu int) error {
+	if mtu < 0 {
+		return fmt.Errorf("soft MTU %d must not be negative", mtu)
+	}
+
+	modem.mtuMutex.Lock()
+	modem.softMtu = mtu
+	modem.mtuMutex.Unlock()
+
+	modem.distributeMtu()
 	return nil
 }
 
-// Mode sets the ModemMode.
-func (modem *Modem) Mode(mode ModemMode) error {
+// checkMode verifies that the ModemMode is known.
+func checkMode(mode ModemMode) error {
 	if int(mode) < 0 || int(mode) > maxModemMode {
 		return fmt.Errorf("modem mode %d is not in [0, %d]", mode, maxModemMode)
 	}
+	return nil
+}
 
-	respMsg, cmdErr := modem.atCommandOnce(fmt.Sprintf("AT+MODE=%d", mode))
-	if cmdErr != nil {
-		return cmdErr
+// checkFrequency verifies that the frequency in MHz is within the tuning range.
+func checkFrequency(frequency float64) error {
+	if frequency < minFrequency || frequency > maxFrequency {
+		return fmt.Errorf("frequency %.2f MHz is not in [%.0f, %.0f]", frequency, minFrequency, maxFrequency)
+	}
+	return nil
+}
+
+// Mode sets the ModemMode.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) Mode(mode ModemMode) error {
+	return modem.withLease(nil, func() error { return modem.mode(mode) })
+}
+
+// mode implements Mode without respecting a ConfigLease.
+func (modem *Modem) mode(mode ModemMode) error {
+	if err := checkMode(mode); err != nil {
+		return err
 	}
-	if !strings.HasPrefix(respMsg, "+OK") {
-		return fmt.Errorf("changing modem mode returned unexpected response: %s", respMsg)
+
+	if _, cmdErr := modem.execute(fmt.Sprintf("AT+MODE=%d", mode)); cmdErr != nil {
+		return cmdErr
 	}
 
 	return modem.refreshMtu()
 }
 
 // Frequency changes the frequency specified in MHz.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
 func (modem *Modem) Frequency(frequency float64) error {
-	respMsg, cmdErr := modem.atCommandOnce(fmt.Sprintf("AT+FREQ=%.2f", frequency))
+	return modem.withLease(nil, func() error { return modem.frequency(frequency) })
+}
+
+// frequency implements Frequency without respecting a ConfigLease.
+func (modem *Modem) frequency(frequency float64) error {
+	if err := checkFrequency(frequency); err != nil {
+		return err
+	}
+
+	tuned := modem.correction().apply(frequency)
+	cmd := modem.frequencyCmd(tuned)
+	results, cmdErr := modem.execute(cmd)
 	if cmdErr != nil {
 		return cmdErr
+	} else if err := modem.verifyFrequency(cmd, tuned, results[0]); err != nil {
+		return err
 	}
-	if !strings.HasPrefix(respMsg, "+FREQ: ") {
-		return fmt.Errorf("changing frequency returned unexpected response: %s", respMsg)
+
+	return modem.refreshMtu()
+}
+
+// SetReceive switches the rf95modem's RX listener on or off.
+//
+// Transmit-only nodes might disable reception to save power and to spare
+// their handlers. The current state is reported as the Status' Receive.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) SetReceive(enabled bool) error {
+	return modem.withLease(nil, func() error { return modem.setReceive(enabled) })
+}
+
+// setReceive implements SetReceive without respecting a ConfigLease.
+func (modem *Modem) setReceive(enabled bool) error {
+	cmd := "AT+RX=0"
+	if enabled {
+		cmd = "AT+RX=1"
+	}
+
+	_, err := modem.execute(cmd)
+	return err
+}
+
+// Configure both the frequency in MHz and the ModemMode at once.
+//
+// Both commands are pipelined, followed by a single MTU refresh. This reduces
+// the setup latency over high-latency links compared to Frequency and Mode.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) Configure(frequency float64, mode ModemMode) error {
+	return modem.withLease(nil, func() error { return modem.configure(frequency, mode) })
+}
+
+// configure implements Configure without respecting a ConfigLease.
+func (modem *Modem) configure(frequency float64, mode ModemMode) error {
+	if err := checkFrequency(frequency); err != nil {
+		return err
+	} else if err := checkMode(mode); err != nil {
+		return err
+	}
+
+	tuned := modem.correction().apply(frequency)
+	cmd := modem.frequencyCmd(tuned)
+	results, cmdErr := modem.execute(cmd, fmt.Sprintf("AT+MODE=%d", mode))
+	if cmdErr != nil {
+		return cmdErr
+	} else if err := modem.verifyFrequency(cmd, tuned, results[0]); err != nil {
+		return err
 	}
 
 	return modem.refreshMtu()
 }
 
+// DryRun validates a change of the ModemMode and frequency without applying it.
+//
+// The returned ConfigReport compares the current configuration against the
+// requested one and lists its Violations, e.g., a ModemMode or frequency the
+// rf95modem does not support. Only the Status is queried from the rf95modem;
+// an error is only returned if this query fails.
+func (modem *Modem) DryRun(mode ModemMode, frequency float64) (report ConfigReport, err error) {
+	if report.Current, err = modem.FetchStatus(); err != nil {
+		return
+	}
+
+	for _, check := range []error{checkMode(mode), checkFrequency(frequency)} {
+		if check != nil {
+			report.Violations = append(report.Violations, check)
+		}
+	}
+
+	report.Mode = mode
+	report.Frequency = frequency
+	report.CurrentAirtime = report.Current.Mode.Airtime(report.Current.Mtu)
+	report.Airtime = mode.Airtime(report.Current.Mtu)
+	return
+}
+
 // FetchStatus queries the status information from AT+INFO.
 func (modem *Modem) FetchStatus() (status Status, err error) {
+	results, cmdErr := modem.execute("AT+INFO")
+	if cmdErr != nil {
+		err = cmdErr
+		return
+	}
+	respMsgs := results[0].lines
+	modem.SetDialect(detectDialect(respMsgs[len(respMsgs)-1]))
+
 	defer func() {
 		if err != nil {
 			status = Status{}
+			err = modem.responseError("AT+INFO", respMsgs, err)
 		}
 	}()
 
-	respMsgs, cmdErr := modem.atCommand(
-		"AT+INFO",
-		func(line string) bool { return !strings.HasPrefix(line, "+OK") })
-	if cmdErr != nil {
-		err = cmdErr
-		return
-	}
-
 	for _, respMsg := range respMsgs {
-		respMsgFilter := regexp.MustCompile(`^(\+STATUS:|\+OK|)\r?\n$`)
+		respMsgFilter := regexp.MustCompile(`^(\+STATUS:|\+OK|\+ Ok\.|)\r?\n$`)
 		if respMsgFilter.MatchString(respMsg) {
 			continue
 		}
@@ -349,10 +799,7 @@ func (modem *Modem) FetchStatus() (status Status, err error) {
 			status.Firmware = value
 
 		case "features":
-			status.Features = strings.Split(value, " ")
-			for i := 0; i < len(status.Features); i++ {
-				status.Features[i] = strings.TrimSpace(status.Features[i])
-			}
+			status.Features = ParseFeatures(value)
 
 		case "modem config":
 			cfgRegexp := regexp.MustCompile(`^(\d+) .*`)
@@ -374,10 +821,10 @@ func (modem *Modem) FetchStatus() (status Status, err error) {
 				err = freqErr
 				return
 			} else {
-				status.Frequency = freq
+				status.Frequency = modem.correction().revert(freq)
 			}
 
-		case "max pkt size", "BFB", "rx bad", "rx good", "tx good":
+		case "max pkt size", "BFB", "tx power", "rx bad", "rx good", "tx good":
 			v, vErr := strconv.Atoi(value)
 			if vErr != nil {
 				err = vErr
@@ -388,6 +835,8 @@ func (modem *Modem) FetchStatus() (status Status, err error) {
 				status.Mtu = v
 			case "BFB":
 				status.Bfb = v
+			case "tx power":
+				status.TxPower = v
 			case "rx bad":
 				status.RxBad = v
 			case "rx good":
@@ -396,8 +845,11 @@ func (modem *Modem) FetchStatus() (status Status, err error) {
 				status.TxGood = v
 			}
 
-		case "rx listener", "GPS":
-			// We don't care about those.
+		case "rx listener":
+			status.Receive = value == "1"
+
+		case "GPS":
+			// We don't care about this one.
 
 		default:
 			err = fmt.Errorf("unknown info key value: %s", key)
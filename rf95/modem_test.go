@@ -1,7 +1,13 @@
 package rf95
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -11,8 +17,10 @@ func TestParseRxMessage(t *testing.T) {
 		errors bool
 		rx     RxMessage
 	}{
-		{"+RX 3,414141,-15,8\n", false, RxMessage{[]byte{0x41, 0x41, 0x41}, -15, 8}},
-		{"+RX 3,ACAB,23,42\n", false, RxMessage{[]byte{0xAC, 0xAB}, 23, 42}},
+		{"+RX 3,414141,-15,8\n", false, RxMessage{Payload: []byte{0x41, 0x41, 0x41}, Rssi: -15, Snr: 8}},
+		{"+RX 2,ACAB,23,42\n", false, RxMessage{Payload: []byte{0xAC, 0xAB}, Rssi: 23, Snr: 42}},
+		{"+RX 3,ACAB,23,42\n", true, RxMessage{}},
+		{"+RX 4,ACABACAB\n", true, RxMessage{}},
 		{"+RX 3,XYZ,23,42\n", true, RxMessage{}},
 		{"+RX 3,1234,F3,42\n", true, RxMessage{}},
 		{"+RX 3,1234,23,F2\n", true, RxMessage{}},
@@ -27,3 +35,369 @@ func TestParseRxMessage(t *testing.T) {
 		}
 	}
 }
+
+// testInfo is an exemplary AT+INFO response of a rf95modem.
+var testInfo = []string{
+	"+STATUS:\n",
+	"\n",
+	"firmware:      0.7.3\n",
+	"features:      LORA GPS BLE\n",
+	"modem config:  0 | Bw125Cr45Sf128\n",
+	"max pkt size:  251\n",
+	"frequency:     868.10\n",
+	"BFB:           0\n",
+	"rx listener:   1\n",
+	"GPS:           0\n",
+	"rx bad:        0\n",
+	"rx good:       0\n",
+	"tx good:       0\n",
+	"+OK\n",
+}
+
+// testDevice emulates a rf95modem's stream for tests.
+//
+// Each written command line is passed to the respond function, whose returned
+// lines are sent back. Additional lines, e.g., +RX messages, can be injected.
+type testDevice struct {
+	respond func(cmd string) []string
+
+	pipeReader *io.PipeReader
+	pipeWriter *io.PipeWriter
+
+	cmdBuff  bytes.Buffer
+	cmdMutex sync.Mutex
+}
+
+// newTestModem creates a Modem backed by a testDevice, answering AT+INFO by default.
+func newTestModem(t testing.TB, respond func(cmd string) []string) (*Modem, *testDevice) {
+	return newTestModemConfig(t, ModemConfig{}, respond)
+}
+
+// newTestModemConfig creates a Modem of the ModemConfig backed by a testDevice, see newTestModem.
+func newTestModemConfig(t testing.TB, config ModemConfig, respond func(cmd string) []string) (*Modem, *testDevice) {
+	dev := &testDevice{respond: func(cmd string) []string {
+		if respond != nil {
+			if lines := respond(cmd); lines != nil {
+				return lines
+			}
+		}
+		if cmd == "AT+INFO" {
+			return testInfo
+		}
+		return []string{"+FAIL\n"}
+	}}
+	dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+	modem, err := OpenModemConfig(dev.pipeReader, dev, dev, config, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = modem.Close() })
+
+	return modem, dev
+}
+
+func (dev *testDevice) Read(p []byte) (int, error) {
+	return dev.pipeReader.Read(p)
+}
+
+func (dev *testDevice) Write(p []byte) (int, error) {
+	dev.cmdMutex.Lock()
+	defer dev.cmdMutex.Unlock()
+
+	_, _ = dev.cmdBuff.Write(p)
+	for {
+		cmd, err := dev.cmdBuff.ReadString('\n')
+		if err != nil {
+			_, _ = dev.cmdBuff.WriteString(cmd)
+			return len(p), nil
+		}
+
+		dev.inject(dev.respond(strings.TrimSpace(cmd))...)
+	}
+}
+
+// inject lines into the stream towards the Modem.
+func (dev *testDevice) inject(lines ...string) {
+	for _, line := range lines {
+		_, _ = dev.pipeWriter.Write([]byte(line))
+	}
+}
+
+func (dev *testDevice) Close() error {
+	return dev.pipeWriter.Close()
+}
+
+func TestModemLinkStats(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := modem.LinkStats()
+	if stats.Commands != 1 {
+		t.Fatalf("link stats counted %d commands, expected 1", stats.Commands)
+	}
+	if stats.BytesWritten != len("AT+INFO\n") {
+		t.Fatalf("link stats counted %d written bytes, expected %d", stats.BytesWritten, len("AT+INFO\n"))
+	}
+	if expected := len(strings.Join(testInfo, "")); stats.BytesRead != expected {
+		t.Fatalf("link stats counted %d read bytes, expected %d", stats.BytesRead, expected)
+	}
+	if u := stats.Utilization(); u <= 0 || u > 1 {
+		t.Fatalf("link utilization is %f, expected (0, 1]", u)
+	}
+}
+
+func TestModemLinkStatsBaud(t *testing.T) {
+	modem, _ := newTestModemConfig(t, ModemConfig{Baud: 1200}, nil)
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+
+	// At 1200 baud, AT+INFO takes at least 66 ms on the wire.
+	if stats, wire := modem.LinkStats(), wireTime(len("AT+INFO\n"), 1200); stats.WriteTime < wire {
+		t.Fatalf("link stats counted a write time of %v, expected at least %v", stats.WriteTime, wire)
+	}
+}
+
+func TestModemRxCorrupted(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	rxMsgs := make(chan RxMessage, 2)
+	if _, err := modem.RegisterHandlers(func(msg RxMessage) { rxMsgs <- msg }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A truncated line, followed by an intact one.
+	dev.inject("+RX 4,ACAB,-15,8\n", "+RX 2,ACAB,-15,8\n")
+
+	if msg := <-rxMsgs; !bytes.Equal(msg.Payload, []byte{0xAC, 0xAB}) {
+		t.Fatalf("received payload %x, expected acab", msg.Payload)
+	}
+	if corrupted := modem.LinkStats().RxCorrupted; corrupted != 1 {
+		t.Fatalf("link stats counted %d corrupted RX lines, expected 1", corrupted)
+	}
+}
+
+func TestModemConfigure(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		switch {
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			return []string{"+FREQ: 868.50\n"}
+		case strings.HasPrefix(cmd, "AT+MODE="):
+			return []string{"+OK\n"}
+		default:
+			return nil
+		}
+	})
+
+	if err := modem.Configure(868.5, FastShortRange); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"AT+FREQ=868.50", "AT+MODE=1", "AT+INFO"}
+	if !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("commands are %v, expected %v", cmds, expected)
+	}
+	if stats := modem.LinkStats(); stats.Commands != 3 {
+		t.Fatalf("link stats counted %d commands, expected 3", stats.Commands)
+	}
+
+	if err := modem.Configure(868.5, ModemMode(23)); err == nil {
+		t.Fatalf("configuring an unknown mode did not error")
+	}
+}
+
+func TestModemFetchStatusAsync(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	done := make(chan Status)
+	modem.FetchStatusAsync(func(status Status, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		done <- status
+	})
+
+	if status := <-done; status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+}
+
+func TestModemSetReceive(t *testing.T) {
+	receive := true
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		switch cmd {
+		case "AT+RX=0", "AT+RX=1":
+			receive = cmd == "AT+RX=1"
+			return []string{"+OK\n"}
+		case "AT+INFO":
+			info := append([]string{}, testInfo...)
+			if !receive {
+				info[8] = "rx listener:   0\n"
+			}
+			return info
+		default:
+			return nil
+		}
+	})
+
+	for _, enabled := range []bool{false, true} {
+		if err := modem.SetReceive(enabled); err != nil {
+			t.Fatal(err)
+		}
+
+		if status, err := modem.FetchStatus(); err != nil {
+			t.Fatal(err)
+		} else if status.Receive != enabled {
+			t.Fatalf("status reports receiving %t, expected %t", status.Receive, enabled)
+		}
+	}
+}
+
+func TestModemConfigLease(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+MODE=") {
+			return []string{"+OK\n"}
+		}
+		return nil
+	})
+
+	lease, err := modem.AcquireConfigLease()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := modem.AcquireConfigLease(); err != ErrConfigLeased {
+		t.Fatalf("acquiring a second lease returned %v, expected %v", err, ErrConfigLeased)
+	}
+	if err := modem.Mode(FastShortRange); err != ErrConfigLeased {
+		t.Fatalf("changing the mode without the lease returned %v, expected %v", err, ErrConfigLeased)
+	}
+	if err := lease.Mode(FastShortRange); err != nil {
+		t.Fatalf("changing the mode with the lease errored: %v", err)
+	}
+
+	lease.Release()
+
+	if err := lease.Mode(FastShortRange); err != ErrConfigLeased {
+		t.Fatalf("changing the mode with a released lease returned %v, expected %v", err, ErrConfigLeased)
+	}
+	if err := modem.Mode(FastShortRange); err != nil {
+		t.Fatalf("changing the mode after the release errored: %v", err)
+	}
+}
+
+func TestTranscriptRing(t *testing.T) {
+	ring := newTranscript(3)
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		ring.record(Sent, line)
+	}
+
+	var lines []string
+	for _, line := range ring.snapshot() {
+		lines = append(lines, line.Line)
+	}
+
+	if expected := []string{"c", "d", "e"}; !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("transcript holds %v, expected %v", lines, expected)
+	}
+}
+
+func TestModemTranscript(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := modem.Transcript()
+	if len(lines) != 1+len(testInfo) {
+		t.Fatalf("transcript holds %d lines, expected %d", len(lines), 1+len(testInfo))
+	}
+	if lines[0].Direction != Sent || lines[0].Line != "AT+INFO" {
+		t.Fatalf("transcript starts with %v, expected the sent AT+INFO", lines[0])
+	}
+	if last := lines[len(lines)-1]; last.Direction != Received || last.Line != "+OK\n" {
+		t.Fatalf("transcript ends with %v, expected the received +OK", last)
+	}
+}
+
+func TestModemResponseError(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+MODE=") {
+			return []string{"+FAIL: nope\n"}
+		}
+		return nil
+	})
+
+	var respErr *ResponseError
+	if err := modem.Mode(FastShortRange); !errors.As(err, &respErr) {
+		t.Fatalf("changing the mode returned %v, expected a ResponseError", err)
+	}
+
+	if respErr.Command != "AT+MODE=1" {
+		t.Fatalf("response error's command is %s, expected AT+MODE=1", respErr.Command)
+	}
+	if expected := []string{"+FAIL: nope\n"}; !reflect.DeepEqual(respErr.Lines, expected) {
+		t.Fatalf("response error's lines are %v, expected %v", respErr.Lines, expected)
+	}
+}
+
+func TestAtResponses(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		line  string
+		match []string
+	}{
+		{"AT+FREQ=868.10", "+FREQ: 868.10\r\n", []string{"+FREQ: 868.10\r\n", "868.10"}},
+		{"AT+INFO", "+OK\n", []string{"+OK"}},
+		{"AT+MODE=1", "+OK\n", []string{"+OK"}},
+		{"AT+MODE=1", "+FAIL\n", nil},
+		{"AT+RX=0", "+OK\n", []string{"+OK"}},
+		{"AT+TX=414141", "+SENT 3 bytes.\n", []string{"+SENT 3 bytes.\n", "3"}},
+		{"AT+TX=414141", "+SENT three bytes.\n", nil},
+	}
+
+	for _, test := range tests {
+		resp, ok := atResponses[atCommandName(test.cmd)]
+		if !ok {
+			t.Fatalf("AT command %s is not registered", test.cmd)
+		}
+
+		if !resp.terminal(test.line) {
+			t.Fatalf("line %q is not terminal for %s", test.line, test.cmd)
+		}
+		if match := resp.pattern.FindStringSubmatch(test.line); !reflect.DeepEqual(match, test.match) {
+			t.Fatalf("line %q for %s matched %q, expected %q", test.line, test.cmd, match, test.match)
+		}
+	}
+}
+
+func TestModemSoftMtu(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	var mtus []int
+	if _, err := modem.RegisterHandlers(nil, func(mtu int) { mtus = append(mtus, mtu) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := modem.SoftMtu(200); err != nil {
+		t.Fatal(err)
+	}
+	if err := modem.SoftMtu(300); err != nil {
+		t.Fatal(err)
+	}
+	if err := modem.SoftMtu(-1); err == nil {
+		t.Fatalf("a negative soft MTU did not error")
+	}
+
+	if expected := []int{251, 200, 251}; !reflect.DeepEqual(mtus, expected) {
+		t.Fatalf("MTU handler received %v, expected %v", mtus, expected)
+	}
+}
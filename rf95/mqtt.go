@@ -0,0 +1,142 @@
+package rf95
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MqttTopics are the topics of a rf95modem's AT interface bridged over MQTT, e.g., by an ESP-based gateway.
+type MqttTopics struct {
+	// Command receives each line written to the rf95modem as a message.
+	Command string
+
+	// Response delivers the rf95modem's output, one or more lines per message.
+	Response string
+
+	// Qos is the MQTT quality of service for both topics. At least once, 1, is
+	// recommended, as lost AT commands or responses stall the Modem.
+	Qos byte
+}
+
+// mqttConn exchanges the rf95modem's stream over MQTT topics.
+type mqttConn struct {
+	client mqtt.Client
+	topics MqttTopics
+
+	pipeReader *io.PipeReader
+	pipeWriter *io.PipeWriter
+}
+
+// OpenMqtt creates a new Modem whose AT interface is bridged over MQTT topics.
+//
+// The mqtt.ClientOptions specify the broker and the client's credentials.
+// Messages must be delivered in order, which is the default. Responses are
+// paired with their AT commands by the Modem, as for a serial connection.
+// The client's automatic reconnect is disabled, as a lost broker connection
+// must reach the Modem: it finishes the Modem or, with a ReconnectPolicy,
+// results in a new connection. For Context information, check OpenModem's
+// documentation; the Context also bounds the connection's establishment.
+func OpenMqtt(options *mqtt.ClientOptions, topics MqttTopics, ctx context.Context) (*Modem, error) {
+	return OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		c := newMqttConn(topics)
+		if err := c.dial(mqtt.NewClient(c.clientOptions(options)), ctx); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}), ctx)
+}
+
+// dialMqtt connects the client and subscribes to the response topic, see mqttConn.dial.
+func dialMqtt(client mqtt.Client, topics MqttTopics, ctx context.Context) (*mqttConn, error) {
+	c := newMqttConn(topics)
+	if err := c.dial(client, ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newMqttConn creates an mqttConn to be connected by dial.
+func newMqttConn(topics MqttTopics) *mqttConn {
+	c := &mqttConn{topics: topics}
+	c.pipeReader, c.pipeWriter = io.Pipe()
+	return c
+}
+
+// clientOptions derives the options of the mqttConn's client, which must not reconnect on its own.
+//
+// A lost connection ends the stream with an error after calling the options' own handler.
+func (c *mqttConn) clientOptions(options *mqtt.ClientOptions) *mqtt.ClientOptions {
+	clientOptions := *options
+	clientOptions.SetAutoReconnect(false)
+	clientOptions.SetConnectionLostHandler(func(client mqtt.Client, err error) {
+		if options.OnConnectionLost != nil {
+			options.OnConnectionLost(client, err)
+		}
+		_ = c.pipeWriter.CloseWithError(fmt.Errorf("MQTT connection lost: %w", err))
+	})
+	return &clientOptions
+}
+
+// dial connects the client and subscribes to the response topic.
+func (c *mqttConn) dial(client mqtt.Client, ctx context.Context) error {
+	c.client = client
+	if err := waitMqtt(client.Connect(), ctx); err != nil {
+		return err
+	}
+
+	if err := waitMqtt(client.Subscribe(c.topics.Response, c.topics.Qos, c.handleResponse), ctx); err != nil {
+		_ = c.Close()
+		return err
+	}
+	return nil
+}
+
+// waitMqtt waits for the Token's completion or for the Context.
+func waitMqtt(token mqtt.Token, ctx context.Context) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleResponse passes a response message into the stream, terminating it by a newline if missing.
+func (c *mqttConn) handleResponse(_ mqtt.Client, msg mqtt.Message) {
+	payload := msg.Payload()
+	if !bytes.HasSuffix(payload, []byte("\n")) {
+		payload = append(payload, '\n')
+	}
+
+	_, _ = c.pipeWriter.Write(payload)
+}
+
+func (c *mqttConn) Read(p []byte) (int, error) {
+	return c.pipeReader.Read(p)
+}
+
+// Write publishes each line as a message to the command topic.
+func (c *mqttConn) Write(p []byte) (n int, err error) {
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		token := c.client.Publish(c.topics.Command, c.topics.Qos, false, line)
+		if token.Wait(); token.Error() != nil {
+			err = token.Error()
+			return
+		}
+		n += len(line)
+	}
+	return
+}
+
+func (c *mqttConn) Close() error {
+	c.client.Disconnect(250)
+	return c.pipeWriter.Close()
+}
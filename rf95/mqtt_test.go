@@ -0,0 +1,143 @@
+package rf95
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// testToken is a completed mqtt.Token.
+type testToken struct {
+	done chan struct{}
+}
+
+func newTestToken() *testToken {
+	token := &testToken{done: make(chan struct{})}
+	close(token.done)
+	return token
+}
+
+func (token *testToken) Wait() bool                     { return true }
+func (token *testToken) WaitTimeout(time.Duration) bool { return true }
+func (token *testToken) Done() <-chan struct{}          { return token.done }
+func (token *testToken) Error() error                   { return nil }
+
+// testMessage is a received mqtt.Message.
+type testMessage struct {
+	mqtt.Message
+	topic   string
+	payload []byte
+}
+
+func (msg *testMessage) Topic() string   { return msg.topic }
+func (msg *testMessage) Payload() []byte { return msg.payload }
+
+// testMqttClient emulates a gateway bridging a rf95modem answering AT+INFO.
+type testMqttClient struct {
+	mqtt.Client
+
+	handlers map[string]mqtt.MessageHandler
+	mutex    sync.Mutex
+}
+
+func (client *testMqttClient) Connect() mqtt.Token { return newTestToken() }
+func (client *testMqttClient) Disconnect(uint)     {}
+
+func (client *testMqttClient) Subscribe(topic string, _ byte, handler mqtt.MessageHandler) mqtt.Token {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.handlers[topic] = handler
+	return newTestToken()
+}
+
+func (client *testMqttClient) Publish(topic string, _ byte, _ bool, payload interface{}) mqtt.Token {
+	if topic == "rf95/cmd" && strings.TrimSpace(string(payload.([]byte))) == "AT+INFO" {
+		client.mutex.Lock()
+		handler := client.handlers["rf95/resp"]
+		client.mutex.Unlock()
+
+		// The gateway publishes each line without its newline.
+		go func() {
+			for _, line := range testInfo {
+				handler(client, &testMessage{topic: "rf95/resp", payload: []byte(strings.TrimSuffix(line, "\n"))})
+			}
+		}()
+	}
+	return newTestToken()
+}
+
+func TestMqttConn(t *testing.T) {
+	client := &testMqttClient{handlers: make(map[string]mqtt.MessageHandler)}
+	topics := MqttTopics{Command: "rf95/cmd", Response: "rf95/resp", Qos: 1}
+
+	var conn *mqttConn
+	modem, err := OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		c, err := dialMqtt(client, topics, ctx)
+		conn = c
+		return c, err
+	}), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+
+	// Without a ReconnectPolicy, the Modem finishes when the bridged stream ends.
+	_ = conn.Close()
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("modem is not finished after the MQTT connection was closed")
+	}
+}
+
+func TestMqttConnLost(t *testing.T) {
+	client := &testMqttClient{handlers: make(map[string]mqtt.MessageHandler)}
+	topics := MqttTopics{Command: "rf95/cmd", Response: "rf95/resp", Qos: 1}
+
+	lost := make(chan error, 1)
+	options := mqtt.NewClientOptions().SetConnectionLostHandler(func(_ mqtt.Client, err error) { lost <- err })
+
+	var clientOptions *mqtt.ClientOptions
+	modem, err := OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		c := newMqttConn(topics)
+		clientOptions = c.clientOptions(options)
+		return c, c.dial(client, ctx)
+	}), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if clientOptions.AutoReconnect {
+		t.Fatal("client reconnects on its own, hiding a lost connection from the Modem")
+	} else if !options.AutoReconnect {
+		t.Fatal("the caller's options were altered")
+	}
+
+	// The broker goes away; the caller's handler is still called.
+	brokerErr := errors.New("broker gone")
+	clientOptions.OnConnectionLost(client, brokerErr)
+
+	if err := <-lost; err != brokerErr {
+		t.Fatalf("connection lost handler got %v, expected %v", err, brokerErr)
+	}
+
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("modem is not finished after the MQTT connection was lost")
+	}
+}
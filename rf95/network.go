@@ -0,0 +1,74 @@
+package rf95
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+)
+
+// OpenTLS creates a new Modem based on a TLS connection to a remote rf95modem.
+//
+// The address is a host and port, e.g., of a serial device server exposing the
+// rf95modem. The tls.Config specifies the server's verification, e.g., by
+// RootCAs and ServerName, client certificates for mutual authentication, and
+// the allowed cipher suites. For Context information, check OpenModem's
+// documentation; the Context also bounds the connection's establishment.
+func OpenTLS(address string, config *tls.Config, ctx context.Context) (*Modem, error) {
+	dialer := &tls.Dialer{Config: config}
+	return OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return dialer.DialContext(ctx, "tcp", address)
+	}), ctx)
+}
+
+// OpenTCP creates a new Modem based on a TCP connection to one of several
+// remote rf95modems, e.g., redundant rf95proxy hosts attached to the same or
+// paired radios.
+//
+// The addresses are hosts and ports, tried in order until a connection is
+// established. After a connection loss, the Modem fails over: the last used
+// address is tried first, followed by all others. Afterwards, the last known
+// frequency and ModemMode are reapplied. Therefore, a default ReconnectPolicy
+// is set, which might be replaced by SetReconnectPolicy; its Open function
+// should be nil to keep the failover. For Context information, check
+// OpenModem's documentation; the Context also bounds the connection's
+// establishment.
+func OpenTCP(addresses []string, ctx context.Context) (*Modem, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no address to connect to")
+	}
+
+	dialer := &net.Dialer{}
+	current := 0
+
+	modem, err := OpenTransport(NewTransport(func(ctx context.Context) (conn io.ReadWriteCloser, err error) {
+		for i := range addresses {
+			next := (current + i) % len(addresses)
+			if conn, err = dialer.DialContext(ctx, "tcp", addresses[next]); err == nil {
+				current = next
+				return
+			}
+		}
+		return
+	}), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modem.SetReconnectPolicy(&ReconnectPolicy{})
+	return modem, nil
+}
+
+// OpenUnix creates a new Modem based on a Unix domain socket at the given path.
+//
+// The socket might be provided by a local broker or by socat, exposing an
+// rf95modem whose serial device is owned by another process. For Context
+// information, check OpenModem's documentation; the Context also bounds the
+// connection's establishment.
+func OpenUnix(path string, ctx context.Context) (*Modem, error) {
+	dialer := &net.Dialer{}
+	return OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return dialer.DialContext(ctx, "unix", path)
+	}), ctx)
+}
@@ -0,0 +1,280 @@
+package rf95
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate creates a self-signed certificate for 127.0.0.1, usable for both server and client.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// serveTestModem answers AT+INFO on each accepted connection.
+func serveTestModem(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if strings.TrimSpace(line) == "AT+INFO" {
+					_, _ = conn.Write([]byte(strings.Join(testInfo, "")))
+				}
+			}
+		}()
+	}
+}
+
+func TestOpenTLS(t *testing.T) {
+	cert, pool := testCertificate(t)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go serveTestModem(listener)
+
+	modem, err := OpenTLS(listener.Addr().String(), &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+}
+
+func TestOpenTCP(t *testing.T) {
+	var listeners [2]net.Listener
+	for i := range listeners {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		listeners[i] = listener
+	}
+
+	// The first proxy accepts a single connection, which is closed on demand.
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listeners[0].Accept()
+		if err != nil {
+			return
+		}
+		_ = listeners[0].Close()
+		accepted <- conn
+
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.TrimSpace(line) == "AT+INFO" {
+				_, _ = conn.Write([]byte(strings.Join(testInfo, "")))
+			}
+		}
+	}()
+	go serveTestModem(listeners[1])
+
+	modem, err := OpenTCP([]string{listeners[0].Addr().String(), listeners[1].Addr().String()}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	reconnected := make(chan error, 1)
+	modem.SetReconnectPolicy(&ReconnectPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		OnReconnect:    func(err error) { reconnected <- err },
+	})
+
+	_ = (<-accepted).Close()
+
+	if err := <-reconnected; err != nil {
+		t.Fatal(err)
+	}
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+
+	if _, err := OpenTCP(nil, context.Background()); err == nil {
+		t.Fatalf("opening without addresses did not error")
+	}
+}
+
+func TestOpenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rf95modem.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go serveTestModem(listener)
+
+	modem, err := OpenUnix(path, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+}
+
+func TestModemPeerClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rf95modem.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	modem, err := OpenUnix(path, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	_ = (<-accepted).Close()
+
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("modem is not finished after the peer closed the connection")
+	}
+
+	if _, err := modem.FetchStatus(); err == nil {
+		t.Fatal("fetching the status of a finished modem succeeded")
+	}
+}
+
+// timeoutReader returns an io.EOF once before reading, as some serial drivers do on a read timeout.
+type timeoutReader struct {
+	io.Reader
+	timedOut bool
+}
+
+func (r *timeoutReader) Read(p []byte) (int, error) {
+	if !r.timedOut {
+		r.timedOut = true
+		return 0, io.EOF
+	}
+	return r.Reader.Read(p)
+}
+
+func TestModemRetryEOF(t *testing.T) {
+	tests := []struct {
+		name     string
+		retryEOF bool
+	}{
+		{"finish", false},
+		{"retry", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dev := &testDevice{respond: func(cmd string) []string { return testInfo }}
+			dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+			modem, err := OpenModemConfig(&timeoutReader{Reader: dev.pipeReader}, dev, dev, ModemConfig{RetryEOF: test.retryEOF}, context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer modem.Close()
+
+			if !test.retryEOF {
+				select {
+				case <-modem.ctx.Done():
+				case <-time.After(3 * time.Second):
+					t.Fatal("modem is not finished after an EOF")
+				}
+				return
+			}
+
+			if _, err := modem.FetchStatus(); err != nil {
+				t.Fatalf("fetching the status after a retried EOF failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package rf95
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Fix is the receiving host's Position, e.g., reported by a GPS receiver.
+type Fix struct {
+	Position
+
+	// Altitude in meters; zero if unknown.
+	Altitude float64
+
+	// Time of the Fix.
+	Time time.Time
+}
+
+// PositionSource provides the host's current Fix for geotagged receptions.
+//
+// This allows coverage surveys with boards lacking an onboard GPS. Fix is
+// called by the Modem's worker for each reception and must not block.
+type PositionSource interface {
+	// Fix returns the current Fix, or false if none is known.
+	Fix() (Fix, bool)
+}
+
+// SetPositionSource stamps each following RxMessage with the source's Fix; nil disables it.
+func (modem *Modem) SetPositionSource(source PositionSource) {
+	modem.handlerMutex.Lock()
+	defer modem.handlerMutex.Unlock()
+
+	modem.positionSource = source
+}
+
+const (
+	// GpsdAddress is the default address of a host's gpsd.
+	GpsdAddress = "localhost:2947"
+
+	// DefaultGpsdMaxAge is the default age after which a gpsd fix is outdated.
+	DefaultGpsdMaxAge = 10 * time.Second
+)
+
+// GpsdSource is a PositionSource fed by gpsd's JSON protocol.
+type GpsdSource struct {
+	// MaxAge after which the latest fix is outdated and no Position is known.
+	MaxAge time.Duration
+
+	fix      Fix
+	received time.Time
+	mutex    sync.Mutex
+}
+
+// gpsdReport is the part of gpsd's reports relevant for a GpsdSource.
+type gpsdReport struct {
+	Class  string    `json:"class"`
+	Mode   int       `json:"mode"`
+	Time   time.Time `json:"time"`
+	Lat    float64   `json:"lat"`
+	Lon    float64   `json:"lon"`
+	Alt    float64   `json:"alt"`
+	AltHAE *float64  `json:"altHAE"`
+}
+
+// NewGpsdSource connects to gpsd at the address, e.g., GpsdAddress, and follows its fixes until the Context is done.
+func NewGpsdSource(address string, ctx context.Context) (*GpsdSource, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write([]byte("?WATCH={\"enable\":true,\"json\":true};\n")); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("enabling gpsd's watch mode failed: %v", err)
+	}
+
+	source := &GpsdSource{MaxAge: DefaultGpsdMaxAge}
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			source.handle(scanner.Bytes())
+		}
+	}()
+
+	return source, nil
+}
+
+// handle a gpsd report, updating the Fix for TPV reports with at least a 2D fix.
+func (source *GpsdSource) handle(line []byte) {
+	var report gpsdReport
+	if err := json.Unmarshal(line, &report); err != nil || report.Class != "TPV" || report.Mode < 2 {
+		return
+	}
+
+	fix := Fix{
+		Position: Position{Latitude: report.Lat, Longitude: report.Lon},
+		Time:     report.Time,
+	}
+	if report.Mode >= 3 {
+		fix.Altitude = report.Alt
+		if report.AltHAE != nil {
+			fix.Altitude = *report.AltHAE
+		}
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.fix, source.received = fix, time.Now()
+}
+
+// Fix returns the latest fix, unless it is older than MaxAge.
+func (source *GpsdSource) Fix() (Fix, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.received.IsZero() || time.Since(source.received) > source.MaxAge {
+		return Fix{}, false
+	}
+	return source.fix, true
+}
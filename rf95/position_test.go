@@ -0,0 +1,84 @@
+package rf95
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGpsdReports are exemplary reports of gpsd in watch mode.
+var testGpsdReports = []string{
+	`{"class":"VERSION","release":"3.22","proto_major":3,"proto_minor":14}`,
+	`{"class":"TPV","device":"/dev/ttyACM0","mode":1}`,
+	`garbage`,
+	`{"class":"TPV","device":"/dev/ttyACM0","mode":3,"time":"2024-05-01T12:00:00.000Z","lat":50.8105,"lon":8.7730,"alt":180.5,"altHAE":228.1}`,
+}
+
+func TestGpsdSource(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if line, err := bufio.NewReader(conn).ReadString('\n'); err != nil || !strings.HasPrefix(line, "?WATCH=") {
+			return
+		}
+		_, _ = conn.Write([]byte(strings.Join(testGpsdReports, "\n") + "\n"))
+		time.Sleep(time.Second)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := NewGpsdSource(listener.Addr().String(), ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := source.Fix(); ok {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("no fix was received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	modem, dev := newTestModem(t, nil)
+	modem.SetPositionSource(source)
+
+	rxChan := make(chan RxMessage, 1)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dev.inject("+RX 3,414141,-15,8\n")
+	rx := <-rxChan
+
+	expected := Fix{
+		Position: Position{Latitude: 50.8105, Longitude: 8.7730},
+		Altitude: 228.1,
+		Time:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if rx.Fix == nil || *rx.Fix != expected {
+		t.Fatalf("RX fix is %v, expected %v", rx.Fix, expected)
+	}
+
+	source.MaxAge = 0
+	dev.inject("+RX 3,414141,-15,8\n")
+	if rx := <-rxChan; rx.Fix != nil {
+		t.Fatalf("RX fix is %v although outdated, expected none", rx.Fix)
+	}
+}
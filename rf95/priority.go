@@ -0,0 +1,62 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// HandlerPriority orders RX handlers; handlers with a lower priority are executed first.
+type HandlerPriority int
+
+const (
+	// PriorityFilter is meant for handlers inspecting packets first, e.g., for de-duplication.
+	PriorityFilter HandlerPriority = -200
+
+	// PriorityArchive is meant for handlers recording packets, e.g., loggers.
+	PriorityArchive HandlerPriority = -100
+
+	// PriorityDefault is used by RegisterHandlers, e.g., for application logic.
+	PriorityDefault HandlerPriority = 0
+)
+
+// prioritizedHandler is a registered RX handler together with its HandlerPriority.
+type prioritizedHandler struct {
+	handle   func(RxMessage)
+	priority HandlerPriority
+}
+
+// RegisterRxHandler with a HandlerPriority.
+//
+// RX handlers are executed ordered by their priority and, for equal
+// priorities, by their registration. Thus, a de-duplication registered with
+// PriorityFilter sees each RxMessage before an archive with PriorityArchive,
+// which in turn precedes the application's handlers from RegisterHandlers. The
+// returned Context will be done if the Modem is finished. The handler must not
+// be nil.
+func (modem *Modem) RegisterRxHandler(handler func(RxMessage), priority HandlerPriority) (context.Context, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("RX handler must not be nil")
+	}
+
+	modem.handlerMutex.Lock()
+	modem.addRxHandler(handler, priority)
+	modem.handlerMutex.Unlock()
+
+	if err := modem.refreshMtu(); err != nil {
+		return nil, err
+	}
+
+	return modem.ctx, nil
+}
+
+// addRxHandler inserts an RX handler after all others of lower or equal priority; handlerMutex must be held.
+func (modem *Modem) addRxHandler(handler func(RxMessage), priority HandlerPriority) {
+	i := sort.Search(len(modem.rxHandlers), func(i int) bool {
+		return modem.rxHandlers[i].priority > priority
+	})
+
+	modem.rxHandlers = append(modem.rxHandlers, prioritizedHandler{})
+	copy(modem.rxHandlers[i+1:], modem.rxHandlers[i:])
+	modem.rxHandlers[i] = prioritizedHandler{handle: handler, priority: priority}
+}
@@ -0,0 +1,46 @@
+package rf95
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModemRxHandlerPriority(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	var order []string
+	done := make(chan struct{})
+
+	register := func(name string, priority HandlerPriority) {
+		if _, err := modem.RegisterRxHandler(func(RxMessage) { order = append(order, name) }, priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	register("app-1", PriorityDefault)
+	register("archive", PriorityArchive)
+	if _, err := modem.RegisterHandlers(func(RxMessage) { order = append(order, "app-2") }, nil); err != nil {
+		t.Fatal(err)
+	}
+	register("dedup", PriorityFilter)
+	register("late", PriorityDefault+1)
+	register("app-3", PriorityDefault)
+	if _, err := modem.RegisterRxHandler(func(RxMessage) { close(done) }, PriorityDefault+2); err != nil {
+		t.Fatal(err)
+	}
+
+	dev.inject("+RX 2,ACAB,-15,8\n")
+	<-done
+
+	if expected := []string{"dedup", "archive", "app-1", "app-2", "app-3", "late"}; !reflect.DeepEqual(order, expected) {
+		t.Fatalf("handlers were executed as %v, expected %v", order, expected)
+	}
+}
+
+func TestModemRxHandlerNil(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	if _, err := modem.RegisterRxHandler(nil, PriorityDefault); err == nil {
+		t.Fatal("registering a nil RX handler succeeded")
+	}
+}
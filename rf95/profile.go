@@ -0,0 +1,45 @@
+package rf95
+
+// Profile sizes the internal buffers of a Modem, which are allocated by OpenModemConfig.
+//
+// The zero Profile selects the StandardProfile.
+type Profile struct {
+	// MsgQueue is the number of response lines buffered for AT commands.
+	MsgQueue int
+
+	// BackgroundQueue is the number of tasks, e.g., by FetchStatusAsync,
+	// waiting for the background worker.
+	BackgroundQueue int
+
+	// TranscriptLines is the number of lines kept for Transcript. Zero
+	// disables the transcript.
+	TranscriptLines int
+}
+
+var (
+	// StandardProfile is suitable for most hosts.
+	StandardProfile = Profile{
+		MsgQueue:        128,
+		BackgroundQueue: 16,
+		TranscriptLines: transcriptSize,
+	}
+
+	// TinyProfile targets gateways with 32 to 64 MB RAM, e.g., OpenWrt routers or
+	// a Raspberry Pi Zero. It keeps no transcript and uses small queues. A Modem
+	// then occupies less than 10 KiB of heap, compared to about 24 KiB
+	// otherwise, next to the stacks of its three goroutines. Independent of the
+	// Profile, an RxMessage is parsed within three allocations.
+	TinyProfile = Profile{
+		MsgQueue:        16,
+		BackgroundQueue: 4,
+		TranscriptLines: 0,
+	}
+)
+
+// orStandard returns the StandardProfile for the zero Profile, otherwise the Profile itself.
+func (profile Profile) orStandard() Profile {
+	if profile == (Profile{}) {
+		return StandardProfile
+	}
+	return profile
+}
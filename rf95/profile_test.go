@@ -0,0 +1,83 @@
+package rf95
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// modemFootprint measures the mean heap size of a Modem opened with the Profile.
+func modemFootprint(t *testing.T, profile Profile) uint64 {
+	const n = 64
+	modems := make([]*Modem, n)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	for i := range modems {
+		pipeReader, _ := io.Pipe()
+		modem, err := OpenModemConfig(pipeReader, io.Discard, nil, ModemConfig{Profile: profile}, context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		modems[i] = modem
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	for _, modem := range modems {
+		_ = modem.Close()
+	}
+
+	return (after.HeapAlloc - before.HeapAlloc) / n
+}
+
+func TestModemConfigProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		want    Profile
+	}{
+		{"zero", Profile{}, StandardProfile},
+		{"standard", StandardProfile, StandardProfile},
+		{"tiny", TinyProfile, TinyProfile},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pipeReader, _ := io.Pipe()
+			modem, err := OpenModemConfig(pipeReader, io.Discard, nil, ModemConfig{Profile: test.profile}, context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer modem.Close()
+
+			got := Profile{
+				MsgQueue:        cap(modem.msgQueue),
+				BackgroundQueue: cap(modem.bgQueue),
+				TranscriptLines: len(modem.transcript.lines),
+			}
+			if got != test.want {
+				t.Fatalf("Modem has buffers %+v, expected %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTinyProfile(t *testing.T) {
+	if footprint := modemFootprint(t, TinyProfile); footprint > 10<<10 {
+		t.Fatalf("Modem of the TinyProfile occupies %d bytes, expected less than 10 KiB", footprint)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := parsePacketRx(benchRxLine); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 3 {
+		t.Fatalf("parsing an RxMessage takes %.0f allocations, expected at most 3", allocs)
+	}
+}
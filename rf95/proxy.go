@@ -0,0 +1,329 @@
+package rf95
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Permission of a client connected to a Server.
+type Permission int
+
+const (
+	// ReadOnly clients receive all RX lines and might query the rf95modem by
+	// AT+INFO and AT+HELP.
+	ReadOnly Permission = iota
+
+	// ReadWrite clients might additionally transmit and change the configuration.
+	ReadWrite
+)
+
+// readOnlyCommands are the AT commands available to ReadOnly clients.
+var readOnlyCommands = map[string]bool{
+	"AT+HELP": true,
+	"AT+INFO": true,
+}
+
+// configCommands are the AT commands changing the configuration, which must respect a ConfigLease.
+var configCommands = map[string]bool{
+	"AT+BFB":  true,
+	"AT+FREQ": true,
+	"AT+MODE": true,
+	"AT+RX":   true,
+	"AT+TXP":  true,
+}
+
+// Server multiplexes several remote clients onto one Modem.
+//
+// Each client speaks the rf95modem's line-based AT protocol, e.g., through
+// netcat or OpenModem on a net.Conn. Commands of all clients are serialized and RX lines are
+// broadcast to all clients. Only registered AT commands are forwarded. AT+TX
+// is checked against the Mtu and sent by Modem.Transmit, passing all
+// TxMiddlewares, e.g., a duty cycle check. AT+FREQ, AT+MODE, and AT+TXP are
+// validated like by the Modem's setters, e.g., Frequency, and against the
+// Server's Region.
+type Server struct {
+	// Permissions decides each client's Permission by its remote address. If
+	// nil, all clients have ReadWrite permissions.
+	Permissions func(net.Addr) Permission
+
+	// Chaos selects the degradation towards each client by its remote
+	// address, e.g., for resilience testing. If nil or returning nil, clients
+	// are served regularly.
+	Chaos func(net.Addr) *Chaos
+
+	// Region limits the frequency and the airtime of the ModemMode clients
+	// might configure, e.g., RegionEU868. The zero Region has no limits.
+	Region Region
+
+	modem *Modem
+
+	clients      map[*serverClient]struct{}
+	clientsMutex sync.Mutex
+}
+
+// serverClientQueue is the number of RX lines queued for a client before it is disconnected as too slow.
+const serverClientQueue = 64
+
+// serverClient is a connection to a Server's client.
+type serverClient struct {
+	conn       net.Conn
+	permission Permission
+	chaos      *Chaos
+	writeMutex sync.Mutex
+
+	// rxQueue holds RX lines until they are written by sendRx. It is closed
+	// when the client is removed, both under the Server's clientsMutex.
+	rxQueue chan string
+}
+
+// sendRx writes the queued RX lines to the client until its rxQueue is closed.
+func (client *serverClient) sendRx() {
+	for line := range client.rxQueue {
+		if err := client.write(line); err != nil {
+			_ = client.conn.Close()
+		}
+	}
+}
+
+// write the lines to the client at once, possibly degraded by its Chaos.
+func (client *serverClient) write(lines ...string) error {
+	client.writeMutex.Lock()
+	defer client.writeMutex.Unlock()
+
+	if client.chaos != nil {
+		if client.chaos.disconnect() {
+			_ = client.conn.Close()
+			return net.ErrClosed
+		}
+		time.Sleep(client.chaos.Latency)
+	}
+
+	_, err := client.conn.Write([]byte(strings.Join(lines, "")))
+	return err
+}
+
+// NewServer for the Modem.
+//
+// This function registers itself with its handler functions at the Modem.
+func NewServer(modem *Modem) (*Server, error) {
+	server := &Server{
+		modem:   modem,
+		clients: make(map[*serverClient]struct{}),
+	}
+
+	if _, err := modem.RegisterHandlers(server.handleRx, nil); err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// Serve the Modem to clients accepted from the net.Listener, using a new Server.
+func Serve(listener net.Listener, modem *Modem) error {
+	server, err := NewServer(modem)
+	if err != nil {
+		return err
+	}
+
+	return server.Serve(listener)
+}
+
+// Serve clients accepted from the net.Listener until it fails or the Modem is finished.
+//
+// The net.Listener is closed afterwards.
+func (server *Server) Serve(listener net.Listener) error {
+	go func() {
+		<-server.modem.ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			_ = listener.Close()
+			return err
+		}
+
+		permission := ReadWrite
+		if server.Permissions != nil {
+			permission = server.Permissions(conn.RemoteAddr())
+		}
+
+		var chaos *Chaos
+		if server.Chaos != nil {
+			chaos = server.Chaos(conn.RemoteAddr())
+		}
+
+		go server.handle(&serverClient{
+			conn:       conn,
+			permission: permission,
+			chaos:      chaos,
+			rxQueue:    make(chan string, serverClientQueue),
+		})
+	}
+}
+
+// handle a client's commands until its connection is closed.
+func (server *Server) handle(client *serverClient) {
+	server.clientsMutex.Lock()
+	server.clients[client] = struct{}{}
+	server.clientsMutex.Unlock()
+
+	go client.sendRx()
+
+	defer func() {
+		server.clientsMutex.Lock()
+		delete(server.clients, client)
+		close(client.rxQueue)
+		server.clientsMutex.Unlock()
+
+		_ = client.conn.Close()
+	}()
+
+	reader := bufio.NewReader(client.conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.TrimSpace(line)
+		if cmd == "" {
+			continue
+		}
+
+		lines, err := server.forward(cmd, client.permission)
+		if err != nil {
+			lines = []string{fmt.Sprintf("+FAIL: %v\n", err)}
+		}
+
+		if err := client.write(lines...); err != nil {
+			return
+		}
+	}
+}
+
+// forward a client's AT command to the Modem, if permitted, and return the raw response lines.
+func (server *Server) forward(cmd string, permission Permission) (lines []string, err error) {
+	name := atCommandName(cmd)
+
+	resp, ok := server.modem.Dialect().responses()[name]
+	if !ok {
+		err = fmt.Errorf("AT command %s is not supported", name)
+		return
+	} else if permission == ReadOnly && !readOnlyCommands[name] {
+		err = fmt.Errorf("AT command %s is not permitted", name)
+		return
+	}
+
+	if name == "AT+TX" {
+		lines, err = server.transmit(cmd)
+		return
+	}
+
+	exec := func() error {
+		responses, pipelineErr := server.modem.atPipeline([]atRequest{{cmd, func(line string) bool { return !resp.terminal(line) }}})
+		if pipelineErr != nil {
+			return pipelineErr
+		}
+		lines = responses[0]
+		return nil
+	}
+
+	if !configCommands[name] {
+		err = exec()
+		return
+	} else if err = server.validate(name, cmd); err != nil {
+		return
+	}
+
+	err = server.modem.withLease(nil, func() error {
+		if execErr := exec(); execErr != nil {
+			return execErr
+		}
+		return server.modem.refreshMtu()
+	})
+	return
+}
+
+// validate a client's configuration command like the Modem's setters and against the Region.
+func (server *Server) validate(name, cmd string) error {
+	_, arg, _ := strings.Cut(cmd, "=")
+
+	switch name {
+	case "AT+FREQ":
+		frequency, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("frequency %q is invalid", arg)
+		} else if err := checkFrequency(frequency); err != nil {
+			return err
+		}
+		return server.Region.checkFrequency(frequency)
+
+	case "AT+MODE":
+		mode, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("modem mode %q is invalid", arg)
+		} else if err := checkMode(ModemMode(mode)); err != nil {
+			return err
+		}
+		return server.Region.checkAirtime(ModemMode(mode).Airtime(server.modem.Mtu()))
+
+	case "AT+TXP":
+		dbm, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("transmit power %q is invalid", arg)
+		}
+		return checkTxPower(dbm)
+	}
+	return nil
+}
+
+// transmit a client's AT+TX command by Modem.Transmit, thus passing all TxMiddlewares.
+func (server *Server) transmit(cmd string) (lines []string, err error) {
+	payload, err := hex.DecodeString(strings.TrimPrefix(cmd, "AT+TX="))
+	if err != nil {
+		return
+	} else if mtu := server.modem.Mtu(); len(payload) > mtu {
+		err = fmt.Errorf("payload of %d bytes exceeds the MTU of %d bytes", len(payload), mtu)
+		return
+	}
+
+	n, err := server.modem.Transmit(payload)
+	if err != nil {
+		return
+	}
+
+	lines = []string{fmt.Sprintf("+SENT %d bytes.\n", n)}
+	return
+}
+
+// handleRx broadcasts received messages to all clients.
+//
+// As this runs on the Modem's worker, lines are only queued for each client.
+// A client whose queue is full, e.g., as it stopped reading, is disconnected
+// instead of stalling the Modem.
+func (server *Server) handleRx(rx RxMessage) {
+	line := fmt.Sprintf("+RX %d,%s,%d,%d\n", len(rx.Payload), strings.ToUpper(hex.EncodeToString(rx.Payload)), rx.Rssi, rx.Snr)
+
+	server.clientsMutex.Lock()
+	defer server.clientsMutex.Unlock()
+
+	for client := range server.clients {
+		if client.chaos != nil && client.chaos.drop() {
+			continue
+		}
+
+		select {
+		case client.rxQueue <- line:
+		default:
+			_ = client.conn.Close()
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package rf95
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	modem, dev := newTestModem(t, func(cmd string) []string {
+		if cmd == "AT+TX=41" {
+			return []string{"+SENT 1 bytes.\n"}
+		}
+		return nil
+	})
+
+	server, err := NewServer(modem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first client might transmit, all others are read-only.
+	var clients int32
+	server.Permissions = func(net.Addr) Permission {
+		if atomic.AddInt32(&clients, 1) == 1 {
+			return ReadWrite
+		}
+		return ReadOnly
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve(listener) }()
+
+	dial := func() (net.Conn, *bufio.Reader) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn, bufio.NewReader(conn)
+	}
+
+	request := func(conn net.Conn, reader *bufio.Reader, cmd string) string {
+		if _, err := conn.Write([]byte(cmd + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		return line
+	}
+
+	rwConn, rwReader := dial()
+	if line := request(rwConn, rwReader, "AT+TX=41"); line != "+SENT 1 bytes.\n" {
+		t.Fatalf("read-write client got %q", line)
+	}
+
+	roConn, roReader := dial()
+	if line := request(roConn, roReader, "AT+TX=41"); !strings.HasPrefix(line, "+FAIL") {
+		t.Fatalf("read-only client got %q", line)
+	}
+	if line := request(roConn, roReader, "AT+INFO"); line != testInfo[0] {
+		t.Fatalf("read-only client got %q", line)
+	}
+	for range testInfo[1:] {
+		if _, err := roReader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dev.inject("+RX 3,414141,-15,8\n")
+	for _, reader := range []*bufio.Reader{rwReader, roReader} {
+		if line, err := reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		} else if line != "+RX 3,414141,-15,8\n" {
+			t.Fatalf("client got %q", line)
+		}
+	}
+}
+
+func TestServerChaos(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	server, err := NewServer(modem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chaos := []*Chaos{{DropRate: 1}, {Latency: 50 * time.Millisecond}, {DisconnectRate: 1}}
+	var clients int32
+	server.Chaos = func(net.Addr) *Chaos {
+		return chaos[atomic.AddInt32(&clients, 1)-1]
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve(listener) }()
+
+	var conns [3]net.Conn
+	var readers [3]*bufio.Reader
+	for i := range conns {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns[i], readers[i] = conn, bufio.NewReader(conn)
+
+		// Wait for the client to be registered by a request.
+		if _, err := conn.Write([]byte("AT+INFO\n")); err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 {
+			if _, err := readers[i].ReadString('\n'); err == nil {
+				t.Fatalf("disconnecting client received a response")
+			}
+			continue
+		}
+		for range testInfo {
+			if _, err := readers[i].ReadString('\n'); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	start := time.Now()
+	dev.inject("+RX 3,414141,-15,8\n")
+
+	if line, err := readers[1].ReadString('\n'); err != nil {
+		t.Fatal(err)
+	} else if line != "+RX 3,414141,-15,8\n" {
+		t.Fatalf("delayed client received %q, expected the RX line", line)
+	} else if delay := time.Since(start); delay < 50*time.Millisecond {
+		t.Fatalf("delayed client received the RX line after %v, expected at least 50ms", delay)
+	}
+
+	if _, err := conns[0].Write([]byte("AT+INFO\n")); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := readers[0].ReadString('\n'); err != nil {
+		t.Fatal(err)
+	} else if line != testInfo[0] {
+		t.Fatalf("dropping client received %q, expected the AT+INFO response", line)
+	}
+}
+
+// pipeListener accepts the server ends of net.Pipes, whose writes block until the client reads.
+type pipeListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+// dial a new connection, returning the client's end.
+func (listener *pipeListener) dial() net.Conn {
+	serverConn, clientConn := net.Pipe()
+	listener.conns <- serverConn
+	return clientConn
+}
+
+func (listener *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-listener.conns:
+		return conn, nil
+	case <-listener.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (listener *pipeListener) Close() error {
+	listener.once.Do(func() { close(listener.done) })
+	return nil
+}
+
+func (listener *pipeListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: "pipe", Net: "pipe"}
+}
+
+func TestServerSlowClient(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	listener := newPipeListener()
+	defer listener.Close()
+	go func() { _ = Serve(listener, modem) }()
+
+	conn := listener.dial()
+	defer conn.Close()
+
+	// Wait for the client to be registered by a request; afterwards, it stops reading.
+	if _, err := conn.Write([]byte("AT+INFO\n")); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	for range testInfo {
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2*serverClientQueue; i++ {
+		dev.inject("+RX 3,414141,-15,8\n")
+	}
+
+	statusErr := make(chan error, 1)
+	go func() {
+		_, err := modem.FetchStatus()
+		statusErr <- err
+	}()
+
+	select {
+	case err := <-statusErr:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("slow client stalled the modem")
+	}
+
+	// The slow client was disconnected.
+	for {
+		if _, err := reader.ReadString('\n'); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestServerTransmit(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+TX=") {
+			cmds = append(cmds, cmd)
+			return []string{fmt.Sprintf("+SENT %d bytes.\n", len(cmd[len("AT+TX="):])/2)}
+		}
+		return nil
+	})
+
+	// Only a single transmission is permitted, e.g., by a duty cycle.
+	var budget int32 = 1
+	modem.UseTx(func(next TxHandler) TxHandler {
+		return func(p []byte) (int, error) {
+			if atomic.AddInt32(&budget, -1) < 0 {
+				return 0, fmt.Errorf("duty cycle exceeded")
+			}
+			return next(p)
+		}
+	})
+
+	listener := newPipeListener()
+	defer listener.Close()
+	go func() { _ = Serve(listener, modem) }()
+
+	conn := listener.dial()
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		cmd    string
+		expect string
+	}{
+		{"AT+TX=" + strings.Repeat("41", 252), "+FAIL"},
+		{"AT+TX=4X", "+FAIL"},
+		{"AT+TX=41", "+SENT 1 bytes."},
+		{"AT+TX=41", "+FAIL: duty cycle exceeded"},
+	}
+
+	for _, test := range tests {
+		if _, err := conn.Write([]byte(test.cmd + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		if line, err := reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		} else if !strings.HasPrefix(line, test.expect) {
+			t.Fatalf("%.20s resulted in %q, expected %q", test.cmd, line, test.expect)
+		}
+	}
+
+	if expected := []string{"AT+TX=41"}; !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("sent %v, expected %v", cmds, expected)
+	}
+}
+
+func TestServerConfigure(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		switch {
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			cmds = append(cmds, cmd)
+			return []string{"+FREQ: " + strings.TrimPrefix(cmd, "AT+FREQ=") + "\n"}
+		case strings.HasPrefix(cmd, "AT+MODE="):
+			cmds = append(cmds, cmd)
+			return []string{"+OK\n"}
+		}
+		return nil
+	})
+
+	server, err := NewServer(modem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Region = RegionUS915
+
+	listener := newPipeListener()
+	defer listener.Close()
+	go func() { _ = server.Serve(listener) }()
+
+	conn := listener.dial()
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		cmd    string
+		expect string
+	}{
+		{"AT+FREQ=2400.00", "+FAIL: frequency 2400.00 MHz is not in"},
+		{"AT+FREQ=868.10", "+FAIL: frequency 868.10 MHz is outside of US915"},
+		{"AT+FREQ=foo", "+FAIL: frequency \"foo\" is invalid"},
+		{"AT+FREQ=915.00", "+FREQ: 915.00"},
+		{"AT+MODE=9", "+FAIL: modem mode 9 is not in"},
+		{"AT+MODE=2", "+FAIL: airtime"},
+		{"AT+MODE=1", "+OK"},
+		{"AT+TXP=30", "+FAIL: transmit power 30 dBm is not in"},
+	}
+
+	for _, test := range tests {
+		if _, err := conn.Write([]byte(test.cmd + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		if line, err := reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		} else if !strings.HasPrefix(line, test.expect) {
+			t.Fatalf("%s resulted in %q, expected %q", test.cmd, line, test.expect)
+		}
+	}
+
+	if expected := []string{"AT+FREQ=915.00", "AT+MODE=1"}; !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("sent %v, expected %v", cmds, expected)
+	}
+}
@@ -0,0 +1,128 @@
+package rf95
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+// PubSubOverhead is the length of the topic hash prepended to each published message.
+const PubSubOverhead = 4
+
+// TopicHash is the 32-bit FNV-1a hash of a topic, identifying it on air.
+func TopicHash(topic string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(topic))
+	return h.Sum32()
+}
+
+// PubSub organizes traffic over a Modem by topics instead of addresses.
+//
+// Each published message starts with its topic's TopicHash. Received messages
+// are passed to the handlers subscribed to their topic; all others are
+// dropped. Topics are matched exactly, as only their hashes are transmitted.
+type PubSub struct {
+	modem *Modem
+
+	subscriptions      map[uint32]map[string][]func(RxMessage)
+	subscriptionsMutex sync.RWMutex
+
+	// mtu is protected through sync/atomic calls.
+	mtu int32
+}
+
+// NewPubSub backed by the given Modem.
+//
+// This function registers itself with its handler functions at the Modem.
+func NewPubSub(modem *Modem) (*PubSub, error) {
+	ps := &PubSub{
+		modem:         modem,
+		subscriptions: make(map[uint32]map[string][]func(RxMessage)),
+	}
+
+	if _, err := modem.RegisterHandlers(ps.handleRx, ps.handleMtu); err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+// handleRx is the rxHandler being passed to the Modem.
+func (ps *PubSub) handleRx(rx RxMessage) {
+	if len(rx.Payload) < PubSubOverhead {
+		return
+	}
+
+	hash := binary.BigEndian.Uint32(rx.Payload[:PubSubOverhead])
+	rx.Payload = rx.Payload[PubSubOverhead:]
+
+	ps.subscriptionsMutex.RLock()
+	defer ps.subscriptionsMutex.RUnlock()
+
+	// Topics of colliding hashes cannot be told apart and are all notified.
+	for _, handlers := range ps.subscriptions[hash] {
+		for _, handler := range handlers {
+			handler(rx)
+		}
+	}
+}
+
+// handleMtu is the mtuHandler passed to the Modem.
+func (ps *PubSub) handleMtu(mtu int) {
+	atomic.StoreInt32(&ps.mtu, int32(mtu))
+}
+
+// Subscribe the handler to messages of the topic.
+func (ps *PubSub) Subscribe(topic string, handler func(RxMessage)) {
+	ps.subscriptionsMutex.Lock()
+	defer ps.subscriptionsMutex.Unlock()
+
+	hash := TopicHash(topic)
+	if ps.subscriptions[hash] == nil {
+		ps.subscriptions[hash] = make(map[string][]func(RxMessage))
+	}
+	ps.subscriptions[hash][topic] = append(ps.subscriptions[hash][topic], handler)
+}
+
+// Unsubscribe all handlers from the topic.
+func (ps *PubSub) Unsubscribe(topic string) {
+	ps.subscriptionsMutex.Lock()
+	defer ps.subscriptionsMutex.Unlock()
+
+	hash := TopicHash(topic)
+	delete(ps.subscriptions[hash], topic)
+	if len(ps.subscriptions[hash]) == 0 {
+		delete(ps.subscriptions, hash)
+	}
+}
+
+// Overhead of the topic hash in bytes, to implement Layer.
+func (ps *PubSub) Overhead() int {
+	return PubSubOverhead
+}
+
+// Mtu returns the maximum payload length left after the topic hash.
+func (ps *PubSub) Mtu() int {
+	return int(atomic.LoadInt32(&ps.mtu)) - PubSubOverhead
+}
+
+// Publish the byte array, whose length must not exceed Mtu, on the topic.
+//
+// The returned length refers to the payload, excluding the topic hash.
+func (ps *PubSub) Publish(topic string, p []byte) (int, error) {
+	if mtu := ps.Mtu(); len(p) > mtu {
+		return 0, fmt.Errorf("payload of %d bytes exceeds the topic MTU of %d bytes", len(p), mtu)
+	}
+
+	msg := make([]byte, PubSubOverhead, PubSubOverhead+len(p))
+	binary.BigEndian.PutUint32(msg, TopicHash(topic))
+	msg = append(msg, p...)
+
+	n, err := ps.modem.Transmit(msg)
+	if n -= PubSubOverhead; n < 0 {
+		n = 0
+	}
+	return n, err
+}
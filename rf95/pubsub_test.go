@@ -0,0 +1,60 @@
+package rf95
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPubSub(t *testing.T) {
+	sent := make(chan string, 1)
+	modem, dev := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+TX=") {
+			payload := strings.TrimPrefix(cmd, "AT+TX=")
+			sent <- payload
+			return []string{fmt.Sprintf("+SENT %d bytes.\n", len(payload)/2)}
+		}
+		return nil
+	})
+
+	ps, err := NewPubSub(modem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan string, 2)
+	ps.Subscribe("sensors/temperature", func(rx RxMessage) { received <- string(rx.Payload) })
+
+	if n, err := ps.Publish("sensors/temperature", []byte("23")); err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("published %d bytes, expected 2", n)
+	}
+
+	payload, _ := hex.DecodeString(<-sent)
+	if hash := binary.BigEndian.Uint32(payload); hash != TopicHash("sensors/temperature") {
+		t.Fatalf("published topic hash %x, expected %x", hash, TopicHash("sensors/temperature"))
+	}
+
+	other := make([]byte, 4)
+	binary.BigEndian.PutUint32(other, TopicHash("sensors/humidity"))
+	dev.inject(
+		fmt.Sprintf("+RX 6,%s,-15,8\n", hex.EncodeToString(append(other, "42"...))),
+		fmt.Sprintf("+RX 6,%s,-15,8\n", hex.EncodeToString(payload)))
+
+	if msg := <-received; msg != "23" {
+		t.Fatalf("received %q, expected 23", msg)
+	}
+
+	ps.Unsubscribe("sensors/temperature")
+	dev.inject(fmt.Sprintf("+RX 6,%s,-15,8\n", hex.EncodeToString(payload)))
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("received %q after unsubscribing", <-received)
+	}
+}
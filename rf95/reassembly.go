@@ -0,0 +1,103 @@
+package rf95
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// rxHeadRegexp matches the first line of a fragmented RX message, lacking RSSI and SNR.
+	rxHeadRegexp = regexp.MustCompile(`^\+RX (\d+),([0-9A-Fa-f]*)\r?\n$`)
+
+	// rxContinuationRegexp matches a line in the middle of a fragmented RX message.
+	rxContinuationRegexp = regexp.MustCompile(`^([0-9A-Fa-f]+)\r?\n$`)
+
+	// rxTailRegexp matches the last line of a fragmented RX message, ending with RSSI and SNR.
+	rxTailRegexp = regexp.MustCompile(`^([0-9A-Fa-f]*)(,[-0-9]+,[-0-9]+)\r?\n$`)
+)
+
+// rxFragment is the state of an rxAssembler after feeding it a line.
+type rxFragment int
+
+const (
+	// rxFragmentPending means the line was consumed and more lines are expected.
+	rxFragmentPending rxFragment = iota
+
+	// rxFragmentComplete means the line completed the RX message.
+	rxFragmentComplete
+
+	// rxFragmentAborted means the line was no fragment and the RX message was dropped.
+	rxFragmentAborted
+)
+
+// rxAssembler reassembles RX messages which are fragmented across multiple serial lines.
+//
+// Some firmware builds split very long +RX notifications, e.g., for large MTUs
+// with BFB, into a head line with the length field and the payload's beginning,
+// hex-only continuation lines, and a tail line ending with the RSSI and SNR.
+type rxAssembler struct {
+	buff   strings.Builder
+	hexLen int
+	length int
+}
+
+// begin a reassembly if the line is the head of a fragmented RX message.
+func (assembler *rxAssembler) begin(line string) bool {
+	findings := rxHeadRegexp.FindStringSubmatch(line)
+	if len(findings) != 3 {
+		return false
+	}
+
+	length, err := strconv.Atoi(findings[1])
+	if err != nil || len(findings[2]) >= 2*length {
+		return false
+	}
+
+	assembler.reset()
+	assembler.length = length
+	assembler.hexLen = len(findings[2])
+	_, _ = assembler.buff.WriteString(strings.TrimRight(line, "\r\n"))
+	return true
+}
+
+// pending checks if a reassembly is in progress.
+func (assembler *rxAssembler) pending() bool {
+	return assembler.buff.Len() > 0
+}
+
+// feed the next line into a pending reassembly.
+//
+// The reassembled RX message is returned for rxFragmentComplete. After
+// rxFragmentAborted, the line was not consumed and must be handled otherwise.
+func (assembler *rxAssembler) feed(line string) (msg string, state rxFragment) {
+	if findings := rxContinuationRegexp.FindStringSubmatch(line); len(findings) == 2 {
+		if assembler.hexLen += len(findings[1]); assembler.hexLen > 2*assembler.length {
+			assembler.reset()
+			return "", rxFragmentAborted
+		}
+
+		_, _ = assembler.buff.WriteString(findings[1])
+		return "", rxFragmentPending
+	}
+
+	if findings := rxTailRegexp.FindStringSubmatch(line); len(findings) == 3 {
+		_, _ = assembler.buff.WriteString(findings[1])
+		_, _ = assembler.buff.WriteString(findings[2])
+		_, _ = assembler.buff.WriteString("\n")
+
+		msg = assembler.buff.String()
+		assembler.reset()
+		return msg, rxFragmentComplete
+	}
+
+	assembler.reset()
+	return "", rxFragmentAborted
+}
+
+// reset the rxAssembler, dropping a pending reassembly.
+func (assembler *rxAssembler) reset() {
+	assembler.buff.Reset()
+	assembler.hexLen = 0
+	assembler.length = 0
+}
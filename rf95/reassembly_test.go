@@ -0,0 +1,75 @@
+package rf95
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRxAssembler(t *testing.T) {
+	tests := []struct {
+		lines  []string
+		states []rxFragment
+		msg    string
+	}{
+		{[]string{"+RX 4,ACAB\n", "ACAB,-15,8\n"}, []rxFragment{rxFragmentComplete}, "+RX 4,ACABACAB,-15,8\n"},
+		{[]string{"+RX 6,AC\r\n", "ABAC\r\n", "AB\r\n", "ACAB,-15,8\r\n"},
+			[]rxFragment{rxFragmentPending, rxFragmentPending, rxFragmentComplete}, "+RX 6,ACABACABACAB,-15,8\n"},
+		{[]string{"+RX 4,\n", "ACABACAB\n", ",-15,8\n"}, []rxFragment{rxFragmentPending, rxFragmentComplete}, "+RX 4,ACABACAB,-15,8\n"},
+		{[]string{"+RX 4,ACAB\n", "+OK\n"}, []rxFragment{rxFragmentAborted}, ""},
+		{[]string{"+RX 2,AC\n", "ACABAC\n"}, []rxFragment{rxFragmentAborted}, ""},
+	}
+
+	for _, test := range tests {
+		var assembler rxAssembler
+		if !assembler.begin(test.lines[0]) {
+			t.Fatalf("%q does not begin a reassembly", test.lines[0])
+		}
+
+		var msg string
+		for i, line := range test.lines[1:] {
+			var state rxFragment
+			if msg, state = assembler.feed(line); state != test.states[i] {
+				t.Fatalf("feeding %q results in state %d, expected %d", line, state, test.states[i])
+			}
+		}
+
+		if msg != test.msg {
+			t.Fatalf("reassembled %q, expected %q", msg, test.msg)
+		} else if assembler.pending() {
+			t.Fatalf("reassembly of %q is still pending", test.lines)
+		}
+	}
+
+	var assembler rxAssembler
+	for _, line := range []string{"+RX 2,ACAB,-15,8\n", "+RX 2,ACAB\n", "+OK\n"} {
+		if assembler.begin(line) {
+			t.Fatalf("%q begins a reassembly", line)
+		}
+	}
+}
+
+func TestModemRxReassembly(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	rxMsgs := make(chan RxMessage, 2)
+	if _, err := modem.RegisterHandlers(func(msg RxMessage) { rxMsgs <- msg }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	dev.inject("+RX 4,ACAB\n", "AC\n", "AB,-15,8\n")
+
+	if msg := <-rxMsgs; !bytes.Equal(msg.Payload, []byte{0xAC, 0xAB, 0xAC, 0xAB}) {
+		t.Fatalf("received payload %x, expected acabacab", msg.Payload)
+	} else if msg.Rssi != -15 || msg.Snr != 8 {
+		t.Fatalf("received RSSI %d and SNR %d, expected -15 and 8", msg.Rssi, msg.Snr)
+	}
+
+	// An interrupted reassembly is dropped, while the interrupting response is still delivered.
+	dev.inject("+RX 4,ACAB\n")
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if corrupted := modem.LinkStats().RxCorrupted; corrupted != 1 {
+		t.Fatalf("link stats counted %d corrupted RX lines, expected 1", corrupted)
+	}
+}
@@ -0,0 +1,162 @@
+package rf95
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrConnectionLost is returned for AT commands interrupted by the loss of the connection to the rf95modem.
+var ErrConnectionLost = errors.New("connection to the rf95modem was lost")
+
+// ReconnectPolicy describes how a Modem reopens its device after an I/O error, e.g., a USB glitch.
+//
+// While reconnecting, AT commands fail and no RxMessages are received.
+// Registered handlers stay in place and continue to work afterwards.
+type ReconnectPolicy struct {
+	// Open the device again, e.g., by SerialOpener. The Context is the Modem's.
+	// For Modems created by OpenTransport, e.g., through OpenSerial, Open might
+	// be nil to redial the Transport instead.
+	Open func(ctx context.Context) (io.ReadWriteCloser, error)
+
+	// InitialBackoff is the delay before the first attempt, doubled after each
+	// failed attempt up to MaxBackoff. Zero values default to half a second
+	// and thirty seconds.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxRetries limits the attempts per disconnect. Zero allows an unlimited
+	// number of attempts. If all attempts fail or if there is neither an Open
+	// function nor a Transport, the Modem is closed.
+	MaxRetries int
+
+	// OnDisconnect is called, if not nil, with the I/O error which caused the
+	// disconnect.
+	OnDisconnect func(error)
+
+	// OnReconnect is called, if not nil, after the device was reopened and the
+	// last known frequency and ModemMode were reapplied. A non-nil error
+	// indicates a failed reconfiguration.
+	OnReconnect func(error)
+}
+
+// nextBackoff returns the delay after a failed attempt, which was delayed by backoff.
+func (policy *ReconnectPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	if backoff *= 2; backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// SetReconnectPolicy enables an automatic reconnect after I/O errors; nil disables it.
+//
+// The frequency and ModemMode to be reapplied are taken from the last MTU
+// refresh, which happens after each configuration change.
+func (modem *Modem) SetReconnectPolicy(policy *ReconnectPolicy) {
+	modem.devMutex.Lock()
+	defer modem.devMutex.Unlock()
+
+	modem.reconnectPolicy = policy
+}
+
+// reconnect the device after the cause, returning the new reader on success.
+//
+// This method is called from the worker, which is thus paused meanwhile.
+func (modem *Modem) reconnect(cause error, policy *ReconnectPolicy) (io.Reader, bool) {
+	modem.devMutex.Lock()
+	close(modem.devLost)
+	if modem.devCloser != nil {
+		_ = modem.devCloser.Close()
+		modem.devCloser = nil
+	}
+	modem.devMutex.Unlock()
+
+	if policy.OnDisconnect != nil {
+		policy.OnDisconnect(cause)
+	}
+
+	if policy.Open == nil && modem.transport == nil {
+		return nil, false
+	}
+
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+
+	for attempt := 1; policy.MaxRetries <= 0 || attempt <= policy.MaxRetries; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-modem.ctx.Done():
+			timer.Stop()
+			return nil, false
+
+		case <-timer.C:
+		}
+
+		dev, err := modem.reopen(policy)
+		if err != nil {
+			backoff = policy.nextBackoff(backoff)
+			continue
+		}
+
+		// Drop responses of the lost connection, which nobody waits for anymore.
+		for drained := false; !drained; {
+			select {
+			case <-modem.msgQueue:
+			default:
+				drained = true
+			}
+		}
+
+		modem.devMutex.Lock()
+		if modem.devClosed {
+			modem.devMutex.Unlock()
+			_ = dev.Close()
+			return nil, false
+		}
+		modem.devReader, modem.devWriter, modem.devCloser = dev, dev, dev
+		modem.devLost = make(chan struct{})
+		state := modem.knownState
+		modem.devMutex.Unlock()
+
+		go modem.restore(state, policy.OnReconnect)
+		return dev, true
+	}
+
+	return nil, false
+}
+
+// reopen the device by the ReconnectPolicy's Open function or by redialing the Transport.
+func (modem *Modem) reopen(policy *ReconnectPolicy) (io.ReadWriteCloser, error) {
+	if policy.Open != nil {
+		return policy.Open(modem.ctx)
+	}
+
+	if err := modem.transport.Redial(modem.ctx); err != nil {
+		return nil, err
+	}
+	return modem.transport, nil
+}
+
+// restore the last known State after a reconnect and report to the callback.
+func (modem *Modem) restore(state *State, callback func(error)) {
+	modem.forgetCommands()
+
+	var err error
+	if state != nil {
+		err = modem.applyState(state)
+	} else {
+		err = modem.refreshMtu()
+	}
+
+	if callback != nil {
+		callback(err)
+	}
+}
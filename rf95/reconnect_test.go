@@ -0,0 +1,110 @@
+package rf95
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestModemReconnect(t *testing.T) {
+	var (
+		cmds      []string
+		cmdsMutex sync.Mutex
+	)
+
+	respond := func(cmd string) []string {
+		cmdsMutex.Lock()
+		cmds = append(cmds, cmd)
+		cmdsMutex.Unlock()
+
+		switch cmd {
+		case "AT+INFO":
+			return testInfo
+		case "AT+FREQ=868.10":
+			return []string{"+FREQ: 868.10\n"}
+		case "AT+MODE=0":
+			return []string{"+OK\n"}
+		default:
+			return []string{"+FAIL\n"}
+		}
+	}
+
+	modem, dev := newTestModem(t, respond)
+
+	rxChan := make(chan RxMessage, 1)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	devChan := make(chan *testDevice, 1)
+	disconnectChan := make(chan error, 1)
+	reconnectChan := make(chan error, 1)
+
+	modem.SetReconnectPolicy(&ReconnectPolicy{
+		Open: func(context.Context) (io.ReadWriteCloser, error) {
+			newDev := &testDevice{respond: respond}
+			newDev.pipeReader, newDev.pipeWriter = io.Pipe()
+			devChan <- newDev
+			return newDev, nil
+		},
+		InitialBackoff: 10 * time.Millisecond,
+		OnDisconnect:   func(err error) { disconnectChan <- err },
+		OnReconnect:    func(err error) { reconnectChan <- err },
+	})
+
+	unplugged := errors.New("unplugged")
+	_ = dev.pipeWriter.CloseWithError(unplugged)
+
+	if err := <-disconnectChan; !errors.Is(err, unplugged) {
+		t.Fatalf("disconnect reported %v, expected %v", err, unplugged)
+	}
+	if err := <-reconnectChan; err != nil {
+		t.Fatalf("reconnect reported %v", err)
+	}
+
+	cmdsMutex.Lock()
+	restored := len(cmds) >= 3 && cmds[len(cmds)-3] == "AT+FREQ=868.10" && cmds[len(cmds)-2] == "AT+MODE=0"
+	cmdsMutex.Unlock()
+	if !restored {
+		t.Fatalf("frequency and mode were not restored: %v", cmds)
+	}
+
+	newDev := <-devChan
+	newDev.inject("+RX 3,414141,-15,8\n")
+
+	select {
+	case rx := <-rxChan:
+		if string(rx.Payload) != "AAA" {
+			t.Fatalf("received payload %q, expected AAA", rx.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RX handler was not called after reconnect")
+	}
+}
+
+func TestModemReconnectGiveUp(t *testing.T) {
+	modem, dev := newTestModem(t, nil)
+
+	modem.SetReconnectPolicy(&ReconnectPolicy{
+		Open: func(context.Context) (io.ReadWriteCloser, error) {
+			return nil, errors.New("no such device")
+		},
+		InitialBackoff: time.Millisecond,
+		MaxRetries:     3,
+	})
+
+	_ = dev.pipeWriter.CloseWithError(errors.New("unplugged"))
+
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Modem was not closed after the last retry")
+	}
+
+	if _, err := modem.FetchStatus(); err == nil {
+		t.Fatal("fetching the status of a closed Modem succeeded")
+	}
+}
@@ -0,0 +1,68 @@
+package rf95
+
+import (
+	"fmt"
+	"time"
+)
+
+// Region are the regulatory limits of an ISM band, as checked by DryRunRegion.
+type Region struct {
+	Name string
+
+	// MinFrequency and MaxFrequency bound the band in MHz; zero means no limit.
+	MinFrequency float64
+	MaxFrequency float64
+
+	// MaxDwellTime limits a single packet's time on air; zero means no limit.
+	MaxDwellTime time.Duration
+}
+
+var (
+	// RegionEU868 is the European 863-870 MHz band.
+	RegionEU868 = Region{Name: "EU868", MinFrequency: 863, MaxFrequency: 870}
+
+	// RegionUS915 is the North American 902-928 MHz band with its 400 ms dwell time.
+	RegionUS915 = Region{Name: "US915", MinFrequency: 902, MaxFrequency: 928, MaxDwellTime: 400 * time.Millisecond}
+)
+
+// check the frequency in MHz and a packet's airtime against the Region's limits.
+func (region Region) check(frequency float64, airtime time.Duration) (violations []error) {
+	for _, err := range []error{region.checkFrequency(frequency), region.checkAirtime(airtime)} {
+		if err != nil {
+			violations = append(violations, err)
+		}
+	}
+	return
+}
+
+// checkFrequency verifies that the frequency in MHz is within the Region's band.
+func (region Region) checkFrequency(frequency float64) error {
+	if region.MaxFrequency > 0 && (frequency < region.MinFrequency || frequency > region.MaxFrequency) {
+		return fmt.Errorf("frequency %.2f MHz is outside of %s's [%.0f, %.0f]",
+			frequency, region.Name, region.MinFrequency, region.MaxFrequency)
+	}
+	return nil
+}
+
+// checkAirtime verifies that a packet's airtime is within the Region's dwell time.
+func (region Region) checkAirtime(airtime time.Duration) error {
+	if region.MaxDwellTime > 0 && airtime > region.MaxDwellTime {
+		return fmt.Errorf("airtime %v exceeds %s's dwell time of %v",
+			airtime, region.Name, region.MaxDwellTime)
+	}
+	return nil
+}
+
+// DryRunRegion validates a change of the ModemMode and frequency like DryRun
+// and additionally checks it against the Region's limits.
+//
+// Region violations, e.g., a packet of the MTU's size exceeding the dwell
+// time, are added to the ConfigReport's Violations.
+func (modem *Modem) DryRunRegion(region Region, mode ModemMode, frequency float64) (report ConfigReport, err error) {
+	if report, err = modem.DryRun(mode, frequency); err != nil {
+		return
+	}
+
+	report.Violations = append(report.Violations, region.check(frequency, report.Airtime)...)
+	return
+}
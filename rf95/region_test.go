@@ -0,0 +1,120 @@
+package rf95
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModemDryRun(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		return nil
+	})
+
+	tests := []struct {
+		region     Region
+		mode       ModemMode
+		frequency  float64
+		violations []string
+	}{
+		{RegionEU868, FastShortRange, 868.5, nil},
+		{RegionUS915, FastShortRange, 915.0, nil},
+		{Region{}, ModemMode(23), 868.5, []string{"mode"}},
+		{Region{}, MediumRange, 2400.0, []string{"frequency 2400.00 MHz is not in"}},
+		{RegionEU868, MediumRange, 915.0, []string{"outside of EU868"}},
+		{RegionUS915, SlowLongRange2, 915.0, []string{"exceeds US915's dwell time"}},
+		{RegionUS915, ModemMode(23), 868.5, []string{"mode", "outside of US915"}},
+	}
+
+	for _, test := range tests {
+		cmds = nil
+
+		report, err := modem.DryRunRegion(test.region, test.mode, test.frequency)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Violations) != len(test.violations) {
+			t.Fatalf("dry run of %s, mode %d, %.2f MHz reported %v, expected %v",
+				test.region.Name, test.mode, test.frequency, report.Violations, test.violations)
+		}
+		for i, violation := range report.Violations {
+			if !strings.Contains(violation.Error(), test.violations[i]) {
+				t.Fatalf("violation %q does not mention %q", violation, test.violations[i])
+			}
+		}
+
+		if report.Current.Frequency != 868.1 || report.Mode != test.mode || report.Frequency != test.frequency {
+			t.Fatalf("dry run reported %+v", report)
+		}
+		if len(cmds) != 1 || cmds[0] != "AT+INFO" {
+			t.Fatalf("dry run sent %v, expected only AT+INFO", cmds)
+		}
+	}
+
+	report, err := modem.DryRun(SlowLongRange2, 868.5)
+	if err != nil {
+		t.Fatal(err)
+	} else if report.Airtime <= report.CurrentAirtime {
+		t.Fatalf("airtime of SlowLongRange2 is %v, expected more than the current %v", report.Airtime, report.CurrentAirtime)
+	}
+}
+
+func TestModemDryRunState(t *testing.T) {
+	var cmds []string
+	respond := func(txp bool) func(string) []string {
+		return func(cmd string) []string {
+			cmds = append(cmds, cmd)
+			if cmd != "AT+HELP" {
+				return nil
+			} else if !txp {
+				return testHelp
+			}
+
+			help := append([]string{}, testHelp[:len(testHelp)-1]...)
+			return append(help, "AT+TXP=<dbm>        Set the transmit power.\n", "+OK\n")
+		}
+	}
+
+	modem, _ := newTestModem(t, respond(false))
+	txpModem, _ := newTestModem(t, respond(true))
+
+	tests := []struct {
+		modem      *Modem
+		state      State
+		violations []string
+	}{
+		{modem, State{Mode: MediumRange, Frequency: 868.5}, nil},
+		{modem, State{Mode: MediumRange, Frequency: 868.5, TxPower: 14}, []string{"cannot set the transmit power"}},
+		{txpModem, State{Mode: MediumRange, Frequency: 868.5, TxPower: 14}, nil},
+		{txpModem, State{Mode: MediumRange, Frequency: 868.5, TxPower: 42}, []string{"transmit power 42 dBm is not in"}},
+		{modem, State{Mode: ModemMode(23), Frequency: 868.5, TxPower: 42}, []string{"mode", "transmit power", "cannot set"}},
+	}
+
+	for _, test := range tests {
+		report, err := test.modem.DryRunState(test.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(report.Violations) != len(test.violations) {
+			t.Fatalf("dry run of %+v reported %v, expected %v", test.state, report.Violations, test.violations)
+		}
+		for i, violation := range report.Violations {
+			if !strings.Contains(violation.Error(), test.violations[i]) {
+				t.Fatalf("violation %q does not mention %q", violation, test.violations[i])
+			}
+		}
+
+		if report.TxPower != test.state.TxPower {
+			t.Fatalf("dry run reported a transmit power of %d, expected %d", report.TxPower, test.state.TxPower)
+		}
+	}
+
+	for _, cmd := range cmds {
+		if cmd != "AT+INFO" && cmd != "AT+HELP" {
+			t.Fatalf("dry run sent %s, expected only queries", cmd)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// resetPulse is how long Reset holds the reset line.
+	resetPulse = 100 * time.Millisecond
+
+	// resetSettle is the silence after the boot banner after which Reset considers the rf95modem booted.
+	resetSettle = 500 * time.Millisecond
+
+	// resetBoot bounds the wait for the boot banner and the following silence.
+	resetBoot = 5 * time.Second
+)
+
+// Reset the rf95modem by its reset line and re-establish the command channel.
+//
+// As the firmware offers no AT command for a reset, the device must support
+// ControlLines, e.g., a serial or an RFC 2217 connection. Like esptool for
+// ESP32 boards, DTR is cleared to boot the firmware and RTS is asserted for a
+// moment to pull the EN pin low. The boot banner is discarded until the
+// rf95modem stays silent. Afterwards, the last known frequency and mode are
+// restored, as after a reconnect.
+//
+// Reset fails if the rf95modem does not boot within five seconds or before the
+// Context is done, i.e., sends no boot banner or does not become silent.
+func (modem *Modem) Reset(ctx context.Context) error {
+	lines, err := modem.controlLines()
+	if err != nil {
+		return err
+	}
+
+	modem.atCommandMutex.Lock()
+	err = pulseReset(lines, ctx)
+	if err == nil {
+		err = modem.awaitBoot(ctx)
+	}
+	modem.atCommandMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	modem.devMutex.Lock()
+	state := modem.knownState
+	modem.devMutex.Unlock()
+
+	modem.restore(state, func(restoreErr error) { err = restoreErr })
+	return err
+}
+
+// pulseReset asserts the reset line for resetPulse.
+func pulseReset(lines ControlLines, ctx context.Context) error {
+	if err := lines.SetDtr(false); err != nil {
+		return err
+	} else if err := lines.SetRts(true); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(resetPulse)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		_ = lines.SetRts(false)
+		return ctx.Err()
+
+	case <-timer.C:
+		return lines.SetRts(false)
+	}
+}
+
+// awaitBoot waits for the boot banner of the rf95modem and discards all lines until it is silent for resetSettle.
+//
+// The atCommandMutex must be held, as all other lines are discarded as well.
+func (modem *Modem) awaitBoot(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, resetBoot)
+	defer cancel()
+
+	// Without a banner, the timer stays stopped until the first line arrives.
+	timer := time.NewTimer(resetSettle)
+	timer.Stop()
+	defer timer.Stop()
+
+	booting := false
+	for {
+		select {
+		case <-modem.ctx.Done():
+			return io.EOF
+
+		case <-ctx.Done():
+			if !booting {
+				return fmt.Errorf("rf95modem sent no boot banner: %w", ctx.Err())
+			}
+			return fmt.Errorf("rf95modem did not finish booting: %w", ctx.Err())
+
+		case <-modem.msgQueue:
+			if booting && !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(resetSettle)
+			booting = true
+
+		case <-timer.C:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package rf95
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestModemReset(t *testing.T) {
+	var cmds []string
+	dev := &testControlDevice{testDevice: &testDevice{respond: func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		if cmd == "AT+INFO" {
+			return testInfo
+		}
+		return []string{"+FAIL\n"}
+	}}}
+	dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+	modem, err := OpenTransport(NewTransport(func(context.Context) (io.ReadWriteCloser, error) {
+		return dev, nil
+	}), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if _, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	}
+	cmds = nil
+
+	// The boot banner arrives while Reset waits for the rf95modem to settle.
+	go func() {
+		time.Sleep(2 * resetPulse)
+		dev.inject("ets Jun  8 2016 00:22:57\n", "rf95modem firmware\n")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := modem.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"DTR=false", "RTS=true", "RTS=false"}; !reflect.DeepEqual(dev.lines, expected) {
+		t.Fatalf("control lines were set to %v, expected %v", dev.lines, expected)
+	}
+	if len(cmds) == 0 || cmds[len(cmds)-1] != "AT+INFO" {
+		t.Fatalf("reset sent %v, expected the status to be fetched", cmds)
+	}
+
+	// Without a boot banner, e.g., if the reset line is not wired, the rf95modem is not considered booted.
+	cmds = nil
+
+	silentCtx, silentCancel := context.WithTimeout(context.Background(), 2*resetSettle)
+	defer silentCancel()
+
+	if err := modem.Reset(silentCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("resetting a silent rf95modem returned %v, expected a deadline error", err)
+	}
+	if len(cmds) != 0 {
+		t.Fatalf("reset of a silent rf95modem sent %v, expected nothing", cmds)
+	}
+
+	plainModem, _ := newTestModem(t, nil)
+	if err := plainModem.Reset(ctx); err == nil {
+		t.Fatal("resetting a device without control lines succeeded")
+	}
+}
@@ -0,0 +1,249 @@
+package rf95
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+)
+
+// Telnet commands and options, RFC 854 and RFC 856.
+const (
+	telnetSe   = 240
+	telnetSb   = 250
+	telnetWill = 251
+	telnetWont = 252
+	telnetDo   = 253
+	telnetDont = 254
+	telnetIac  = 255
+
+	telnetBinary  = 0
+	telnetComPort = 44
+)
+
+// Client commands of the Telnet COM-PORT-OPTION, RFC 2217.
+const (
+	comPortSetBaudrate = 1
+	comPortSetDatasize = 2
+	comPortSetParity   = 3
+	comPortSetStopsize = 4
+	comPortSetControl  = 5
+)
+
+// Values of the SET-CONTROL command to set the DTR and RTS lines, RFC 2217.
+const (
+	comPortDtrOn  = 8
+	comPortDtrOff = 9
+	comPortRtsOn  = 11
+	comPortRtsOff = 12
+)
+
+// rfc2217Conn speaks Telnet with the COM-PORT-OPTION of RFC 2217 to a serial device server.
+type rfc2217Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMutex sync.Mutex
+}
+
+// OpenRFC2217 creates a new Modem based on a serial port of a remote device server speaking RFC 2217.
+//
+// Device servers such as ser2net or Moxa's expose serial ports this way. The
+// address is a host and port, the SerialConfig is applied remotely. The
+// Modem's ControlLines are supported. For Context information, check
+// OpenModem's documentation; the Context also bounds the connection's
+// establishment.
+func OpenRFC2217(address string, config SerialConfig, ctx context.Context) (modem *Modem, err error) {
+	if _, err = config.mode(); err != nil {
+		return
+	}
+
+	return OpenTransportConfig(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return dialRFC2217(address, config, ctx)
+	}), ModemConfig{Baud: config.Baud}, ctx)
+}
+
+// dialRFC2217 connects and negotiates the binary transmission and the COM-PORT-OPTION.
+func dialRFC2217(address string, config SerialConfig, ctx context.Context) (*rfc2217Conn, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &rfc2217Conn{conn: conn, reader: bufio.NewReader(conn)}
+	if err := c.negotiate(config); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// negotiate the Telnet options and the SerialConfig without waiting for the server's acknowledgements.
+func (c *rfc2217Conn) negotiate(config SerialConfig) error {
+	baud := make([]byte, 4)
+	binary.BigEndian.PutUint32(baud, uint32(config.Baud))
+
+	// RFC 2217 encodes parity and stop bits starting at one, with 1.5 stop bits last.
+	parity := byte(config.Parity) + 1
+	stopSize := map[StopBits]byte{OneStopBit: 1, TwoStopBits: 2, OnePointFiveStopBits: 3}[config.StopBits]
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	msg := []byte{
+		telnetIac, telnetWill, telnetBinary,
+		telnetIac, telnetDo, telnetBinary,
+		telnetIac, telnetWill, telnetComPort,
+	}
+	msg = append(msg, comPortCommand(comPortSetBaudrate, baud...)...)
+	msg = append(msg, comPortCommand(comPortSetDatasize, 8)...)
+	msg = append(msg, comPortCommand(comPortSetParity, parity)...)
+	msg = append(msg, comPortCommand(comPortSetStopsize, stopSize)...)
+
+	_, err := c.conn.Write(msg)
+	return err
+}
+
+// comPortCommand creates a COM-PORT-OPTION subnegotiation with an escaped value.
+func comPortCommand(cmd byte, value ...byte) []byte {
+	msg := []byte{telnetIac, telnetSb, telnetComPort, cmd}
+	msg = append(msg, telnetEscape(value)...)
+	return append(msg, telnetIac, telnetSe)
+}
+
+// telnetEscape doubles each IAC byte.
+func telnetEscape(p []byte) []byte {
+	escaped := make([]byte, 0, len(p))
+	for _, b := range p {
+		if b == telnetIac {
+			escaped = append(escaped, telnetIac)
+		}
+		escaped = append(escaped, b)
+	}
+	return escaped
+}
+
+// Read data bytes while handling Telnet commands in between.
+func (c *rfc2217Conn) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if n > 0 && c.reader.Buffered() == 0 {
+			return
+		}
+
+		var b byte
+		if b, err = c.reader.ReadByte(); err != nil {
+			return
+		} else if b != telnetIac {
+			p[n] = b
+			n++
+			continue
+		}
+
+		if b, err = c.reader.ReadByte(); err != nil {
+			return
+		}
+
+		switch b {
+		case telnetIac:
+			p[n] = telnetIac
+			n++
+
+		case telnetWill, telnetWont, telnetDo, telnetDont:
+			var option byte
+			if option, err = c.reader.ReadByte(); err != nil {
+				return
+			} else if err = c.answer(b, option); err != nil {
+				return
+			}
+
+		case telnetSb:
+			// The server's notifications and acknowledgements are not evaluated.
+			if err = c.skipSubnegotiation(); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// answer the server's option negotiation by refusing all options but those requested by ourselves.
+func (c *rfc2217Conn) answer(cmd, option byte) error {
+	if option == telnetBinary || option == telnetComPort {
+		return nil
+	}
+
+	var reply byte
+	switch cmd {
+	case telnetDo:
+		reply = telnetWont
+	case telnetWill:
+		reply = telnetDont
+	default:
+		return nil
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err := c.conn.Write([]byte{telnetIac, reply, option})
+	return err
+}
+
+// skipSubnegotiation reads up to and including the closing IAC SE.
+func (c *rfc2217Conn) skipSubnegotiation() error {
+	for {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return err
+		} else if b != telnetIac {
+			continue
+		}
+
+		if b, err = c.reader.ReadByte(); err != nil {
+			return err
+		} else if b == telnetSe {
+			return nil
+		}
+	}
+}
+
+// Write the data bytes, escaping IAC bytes.
+func (c *rfc2217Conn) Write(p []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	if _, err := c.conn.Write(telnetEscape(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *rfc2217Conn) Close() error {
+	return c.conn.Close()
+}
+
+// setControl sends a SET-CONTROL command.
+func (c *rfc2217Conn) setControl(value byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err := c.conn.Write(comPortCommand(comPortSetControl, value))
+	return err
+}
+
+func (c *rfc2217Conn) SetDtr(dtr bool) error {
+	if dtr {
+		return c.setControl(comPortDtrOn)
+	}
+	return c.setControl(comPortDtrOff)
+}
+
+func (c *rfc2217Conn) SetRts(rts bool) error {
+	if rts {
+		return c.setControl(comPortRtsOn)
+	}
+	return c.setControl(comPortRtsOff)
+}
@@ -0,0 +1,104 @@
+package rf95
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestOpenRFC2217(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	config := SerialConfig{Baud: 115200, Parity: EvenParity, StopBits: OnePointFiveStopBits}
+
+	negotiation := []byte{
+		telnetIac, telnetWill, telnetBinary,
+		telnetIac, telnetDo, telnetBinary,
+		telnetIac, telnetWill, telnetComPort,
+		telnetIac, telnetSb, telnetComPort, comPortSetBaudrate, 0x00, 0x01, 0xc2, 0x00, telnetIac, telnetSe,
+		telnetIac, telnetSb, telnetComPort, comPortSetDatasize, 8, telnetIac, telnetSe,
+		telnetIac, telnetSb, telnetComPort, comPortSetParity, 3, telnetIac, telnetSe,
+		telnetIac, telnetSb, telnetComPort, comPortSetStopsize, 3, telnetIac, telnetSe,
+	}
+	control := comPortCommand(comPortSetControl, comPortDtrOn)
+
+	errChan := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			errChan <- err
+			return
+		}
+		defer conn.Close()
+
+		received := make([]byte, len(negotiation))
+		if _, err := io.ReadFull(conn, received); err != nil {
+			errChan <- err
+			return
+		} else if !bytes.Equal(received, negotiation) {
+			errChan <- io.ErrUnexpectedEOF
+			return
+		}
+
+		// Acknowledge the options and answer AT+INFO with an interleaved option request.
+		_, _ = conn.Write([]byte{telnetIac, telnetDo, telnetComPort, telnetIac, telnetSb, telnetComPort, 101, 0x00, 0x01, 0xc2, 0x00, telnetIac, telnetSe})
+
+		c := &rfc2217Conn{conn: conn, reader: bufio.NewReader(conn)}
+		lines := bufio.NewReader(c)
+		if line, err := lines.ReadString('\n'); err != nil {
+			errChan <- err
+			return
+		} else if strings.TrimSpace(line) != "AT+INFO" {
+			errChan <- io.ErrUnexpectedEOF
+			return
+		}
+
+		info := strings.Join(testInfo, "")
+		_, _ = conn.Write([]byte(info[:10]))
+		_, _ = conn.Write([]byte{telnetIac, telnetDo, 1})
+		_, _ = conn.Write([]byte(info[10:]))
+
+		received = make([]byte, 3+len(control))
+		if _, err := io.ReadFull(conn, received); err != nil {
+			errChan <- err
+		} else if !bytes.Equal(received, append([]byte{telnetIac, telnetWont, 1}, control...)) {
+			errChan <- io.ErrUnexpectedEOF
+		} else {
+			errChan <- nil
+		}
+	}()
+
+	modem, err := OpenRFC2217(listener.Addr().String(), config, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+
+	if err := modem.SetDtr(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-errChan; err != nil {
+		t.Fatalf("server failed: %v", err)
+	}
+}
+
+func TestTelnetEscape(t *testing.T) {
+	if escaped := telnetEscape([]byte{0x41, telnetIac, 0x42}); !bytes.Equal(escaped, []byte{0x41, telnetIac, telnetIac, 0x42}) {
+		t.Fatalf("escaped to %x", escaped)
+	}
+}
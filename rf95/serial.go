@@ -0,0 +1,136 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.bug.st/serial"
+)
+
+// Parity of a serial connection.
+type Parity int
+
+const (
+	// NoParity disables the parity bit.
+	NoParity Parity = iota
+
+	// OddParity sets the parity bit for an odd number of set bits.
+	OddParity
+
+	// EvenParity sets the parity bit for an even number of set bits.
+	EvenParity
+)
+
+// StopBits of a serial connection.
+type StopBits int
+
+const (
+	// OneStopBit is a single stop bit.
+	OneStopBit StopBits = iota
+
+	// OnePointFiveStopBits are one and a half stop bits.
+	OnePointFiveStopBits
+
+	// TwoStopBits are two stop bits.
+	TwoStopBits
+)
+
+// SerialConfig describes a serial connection for OpenSerialConfig.
+type SerialConfig struct {
+	// Baud rate, e.g., 115200.
+	Baud int
+
+	// Parity and StopBits of each character. The number of data bits is always eight.
+	Parity   Parity
+	StopBits StopBits
+}
+
+// DefaultSerialConfig is the rf95modem's default serial configuration, used by OpenSerial.
+var DefaultSerialConfig = SerialConfig{
+	Baud:     115200,
+	Parity:   NoParity,
+	StopBits: OneStopBit,
+}
+
+// mode converts the SerialConfig into the serial package's Mode.
+func (config SerialConfig) mode() (mode *serial.Mode, err error) {
+	mode = &serial.Mode{
+		BaudRate: config.Baud,
+		DataBits: 8,
+	}
+
+	switch config.Parity {
+	case NoParity:
+		mode.Parity = serial.NoParity
+	case OddParity:
+		mode.Parity = serial.OddParity
+	case EvenParity:
+		mode.Parity = serial.EvenParity
+	default:
+		err = fmt.Errorf("unknown parity %d", config.Parity)
+		return
+	}
+
+	switch config.StopBits {
+	case OneStopBit:
+		mode.StopBits = serial.OneStopBit
+	case OnePointFiveStopBits:
+		mode.StopBits = serial.OnePointFiveStopBits
+	case TwoStopBits:
+		mode.StopBits = serial.TwoStopBits
+	default:
+		err = fmt.Errorf("unknown number of stop bits %d", config.StopBits)
+		return
+	}
+
+	if config.Baud <= 0 {
+		err = fmt.Errorf("invalid baud rate %d", config.Baud)
+	}
+	return
+}
+
+// OpenSerialConfig creates a new Modem based on a serial connection, configured by the SerialConfig.
+//
+// The device parameter might be /dev/ttyUSB0, COM3, or your operating system's
+// equivalent. The Modem's ControlLines are supported, e.g., to reset a board by
+// a DTR pulse. For Context information, check OpenModem's documentation.
+func OpenSerialConfig(device string, config SerialConfig, ctx context.Context) (modem *Modem, err error) {
+	return OpenTransportConfig(NewTransport(SerialOpener(device, config)), ModemConfig{Baud: config.Baud}, ctx)
+}
+
+// SerialOpener returns a function to open the serial device, e.g., for a ReconnectPolicy.
+//
+// The opened device supports ControlLines.
+func SerialOpener(device string, config SerialConfig) func(context.Context) (io.ReadWriteCloser, error) {
+	return func(_ context.Context) (io.ReadWriteCloser, error) {
+		mode, err := config.mode()
+		if err != nil {
+			return nil, err
+		}
+
+		port, err := serial.Open(device, mode)
+		if err != nil {
+			return nil, err
+		}
+		return &serialPort{port}, nil
+	}
+}
+
+// serialPort is a serial.Port supporting ControlLines.
+type serialPort struct {
+	serial.Port
+}
+
+func (port *serialPort) SetDtr(dtr bool) error {
+	return port.SetDTR(dtr)
+}
+
+func (port *serialPort) SetRts(rts bool) error {
+	return port.SetRTS(rts)
+}
+
+// SerialPorts lists the names of the serial devices available on this system.
+func SerialPorts() ([]string, error) {
+	return serial.GetPortsList()
+}
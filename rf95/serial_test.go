@@ -0,0 +1,26 @@
+package rf95
+
+import (
+	"testing"
+)
+
+func TestSerialConfigMode(t *testing.T) {
+	tests := []struct {
+		config SerialConfig
+		valid  bool
+	}{
+		{DefaultSerialConfig, true},
+		{SerialConfig{Baud: 9600, Parity: EvenParity, StopBits: TwoStopBits}, true},
+		{SerialConfig{Baud: 0}, false},
+		{SerialConfig{Baud: 115200, Parity: Parity(23)}, false},
+		{SerialConfig{Baud: 115200, StopBits: StopBits(23)}, false},
+	}
+
+	for _, test := range tests {
+		if mode, err := test.config.mode(); (err == nil) != test.valid {
+			t.Fatalf("config %v resulted in error %v, expected validity %t", test.config, err, test.valid)
+		} else if err == nil && mode.BaudRate != test.config.Baud {
+			t.Fatalf("mode has baud rate %d, expected %d", mode.BaudRate, test.config.Baud)
+		}
+	}
+}
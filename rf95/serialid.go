@@ -0,0 +1,74 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// serialByIdDir holds persistent links to serial devices, as created by udev on Linux.
+//
+// Their names contain the USB vendor, product, and serial number, e.g.,
+// usb-Silicon_Labs_CP2102_USB_to_UART_Bridge_Controller_0001-if00-port0.
+var serialByIdDir = "/dev/serial/by-id"
+
+// FindSerialById resolves the serial device whose persistent identifier contains id.
+//
+// The id might be a board's USB serial number or the full name of a link in
+// /dev/serial/by-id. Exactly one device must match. Right now, this is only
+// supported on Linux.
+func FindSerialById(id string) (device string, err error) {
+	entries, err := os.ReadDir(serialByIdDir)
+	if err != nil {
+		return
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), id) {
+			matches = append(matches, entry.Name())
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		err = fmt.Errorf("no serial device matches %s", id)
+		return
+	case 1:
+		return filepath.EvalSymlinks(filepath.Join(serialByIdDir, matches[0]))
+	default:
+		err = fmt.Errorf("serial device id %s is ambiguous: %s", id, strings.Join(matches, ", "))
+		return
+	}
+}
+
+// OpenSerialById creates a new Modem based on a serial connection to the device found by FindSerialById.
+//
+// In contrast to OpenSerial, the device is identified independently of the
+// enumeration order of the operating system, e.g., /dev/ttyUSB0 or ttyUSB1.
+func OpenSerialById(id string, ctx context.Context) (*Modem, error) {
+	device, err := FindSerialById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenSerial(device, ctx)
+}
+
+// SerialAliases binds logical names to serial device ids, as used by OpenSerialById.
+//
+// For example, SerialAliases{"gateway": "0001", "tracker": "0002"} binds two
+// boards by their USB serial numbers.
+type SerialAliases map[string]string
+
+// Open the Modem bound to the logical name.
+func (aliases SerialAliases) Open(name string, ctx context.Context) (*Modem, error) {
+	id, ok := aliases[name]
+	if !ok {
+		return nil, fmt.Errorf("no serial device is bound to %s", name)
+	}
+
+	return OpenSerialById(id, ctx)
+}
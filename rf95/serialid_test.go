@@ -0,0 +1,49 @@
+package rf95
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindSerialById(t *testing.T) {
+	devDir := t.TempDir()
+	byIdDir := t.TempDir()
+
+	for _, link := range []struct{ name, target string }{
+		{"usb-Silicon_Labs_CP2102_USB_to_UART_Bridge_Controller_0001-if00-port0", "ttyUSB1"},
+		{"usb-Silicon_Labs_CP2102_USB_to_UART_Bridge_Controller_0002-if00-port0", "ttyUSB0"},
+	} {
+		target := filepath.Join(devDir, link.target)
+		if err := os.WriteFile(target, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, filepath.Join(byIdDir, link.name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldByIdDir := serialByIdDir
+	serialByIdDir = byIdDir
+	defer func() { serialByIdDir = oldByIdDir }()
+
+	tests := []struct {
+		id     string
+		errors bool
+		device string
+	}{
+		{"0001", false, "ttyUSB1"},
+		{"Controller_0002-if00", false, "ttyUSB0"},
+		{"CP2102", true, ""},
+		{"0003", true, ""},
+	}
+
+	for _, test := range tests {
+		device, err := FindSerialById(test.id)
+		if (err != nil) != test.errors {
+			t.Fatalf("id %s returned error %v, expected %t", test.id, err, test.errors)
+		} else if !test.errors && filepath.Base(device) != test.device {
+			t.Fatalf("id %s resolved to %s, expected %s", test.id, device, test.device)
+		}
+	}
+}
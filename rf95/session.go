@@ -0,0 +1,146 @@
+package rf95
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder records a client's session with an rf95modem or a daemon, e.g., rf95proxy.
+//
+// A Recorder wraps the client's connection and is passed to OpenModem instead.
+// All lines written, i.e., requests, and read, i.e., responses and streamed
+// packets, are recorded. The Session, e.g., stored by WriteSession, might be
+// replayed by Replay as a deterministic test server.
+type Recorder struct {
+	conn io.ReadWriteCloser
+
+	lines   []TranscriptLine
+	partial [2]strings.Builder
+	mutex   sync.Mutex
+}
+
+// NewRecorder for the client's connection.
+func NewRecorder(conn io.ReadWriteCloser) *Recorder {
+	return &Recorder{conn: conn}
+}
+
+// record the bytes in the Direction, adding each completed line.
+func (recorder *Recorder) record(direction Direction, p []byte) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	partial := &recorder.partial[direction]
+	for _, b := range p {
+		partial.WriteByte(b)
+		if b == '\n' {
+			recorder.lines = append(recorder.lines, TranscriptLine{time.Now(), direction, partial.String()})
+			partial.Reset()
+		}
+	}
+}
+
+func (recorder *Recorder) Read(p []byte) (n int, err error) {
+	n, err = recorder.conn.Read(p)
+	recorder.record(Received, p[:n])
+	return
+}
+
+func (recorder *Recorder) Write(p []byte) (int, error) {
+	// Requests are recorded first, as their responses might be read before Write returns.
+	recorder.record(Sent, p)
+	return recorder.conn.Write(p)
+}
+
+func (recorder *Recorder) Close() error {
+	return recorder.conn.Close()
+}
+
+// Session returns all lines recorded so far, from oldest to newest.
+func (recorder *Recorder) Session() []TranscriptLine {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append([]TranscriptLine(nil), recorder.lines...)
+}
+
+// WriteSession writes the lines, one per line in the format of DumpTranscript.
+func WriteSession(w io.Writer, lines []TranscriptLine) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSession reads lines written by WriteSession or DumpTranscript.
+//
+// As line endings are not stored, each line ends with a plain newline.
+func ReadSession(r io.Reader) (lines []TranscriptLine, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) < 2 {
+			err = fmt.Errorf("session line %q is malformed", scanner.Text())
+			return
+		}
+
+		var line TranscriptLine
+		if line.Time, err = time.Parse(time.RFC3339Nano, fields[0]); err != nil {
+			return
+		}
+
+		switch fields[1] {
+		case Sent.String():
+			line.Direction = Sent
+		case Received.String():
+			line.Direction = Received
+		default:
+			err = fmt.Errorf("session line %q has an unknown direction", scanner.Text())
+			return
+		}
+
+		if len(fields) == 3 {
+			line.Line = fields[2]
+		}
+		line.Line += "\n"
+
+		lines = append(lines, line)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+// Replay a recorded session as the server side of the connection, e.g., for integration tests.
+//
+// Each Sent line is expected to be read from the connection in order, while
+// each Received line is written back, including streamed packets. Thus, the
+// client is served deterministically, independent of the original timing. An
+// unexpected request results in an error.
+func Replay(conn io.ReadWriter, lines []TranscriptLine) error {
+	reader := bufio.NewReader(conn)
+
+	for _, line := range lines {
+		if line.Direction == Received {
+			if _, err := io.WriteString(conn, line.Line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		request, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		if expected := strings.TrimSpace(line.Line); strings.TrimSpace(request) != expected {
+			return fmt.Errorf("replayed session expected request %q, got %q", expected, strings.TrimSpace(request))
+		}
+	}
+	return nil
+}
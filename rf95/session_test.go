@@ -0,0 +1,73 @@
+package rf95
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestSessionReplay(t *testing.T) {
+	dev := &testDevice{respond: func(cmd string) []string {
+		if cmd == "AT+INFO" {
+			return testInfo
+		}
+		return []string{"+FAIL\n"}
+	}}
+	dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+	// Record a session of a status query and a streamed packet.
+	recorder := NewRecorder(dev)
+	modem, err := OpenModem(recorder, recorder, recorder, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rxChan := make(chan RxMessage, 1)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+	dev.inject("+RX 3,414141,-15,8\n")
+	<-rxChan
+	_ = modem.Close()
+
+	var buf bytes.Buffer
+	if err := WriteSession(&buf, recorder.Session()); err != nil {
+		t.Fatal(err)
+	}
+	session, err := ReadSession(&buf)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(session) != 2+len(testInfo) {
+		t.Fatalf("session holds %d lines, expected %d", len(session), 2+len(testInfo))
+	}
+
+	// Replay the session against a new client.
+	client, server := net.Pipe()
+	replayed := make(chan error, 1)
+	go func() { replayed <- Replay(server, session) }()
+
+	modem, err = OpenModem(client, client, client, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, nil); err != nil {
+		t.Fatal(err)
+	}
+	if rx := <-rxChan; string(rx.Payload) != "AAA" {
+		t.Fatalf("replayed packet is %v, expected AAA", rx)
+	}
+	if err := <-replayed; err != nil {
+		t.Fatal(err)
+	}
+
+	// A diverging client fails the replay.
+	client, server = net.Pipe()
+	go func() { _, _ = client.Write([]byte("AT+MODE=1\n")) }()
+	if err := Replay(server, session); err == nil {
+		t.Fatalf("replaying a diverging session did not error")
+	}
+}
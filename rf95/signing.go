@@ -0,0 +1,314 @@
+package rf95
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// signingKeyIdLen is the length of the key identifier, prepended to a signed message.
+	signingKeyIdLen = 4
+
+	// signingSeqLen is the length of the sequence number, following the key identifier.
+	signingSeqLen = 4
+
+	// SigningOverhead is the number of bytes a signature adds to each message.
+	SigningOverhead = signingKeyIdLen + signingSeqLen + ed25519.SignatureSize
+
+	// replayWindowSize is the number of sequence numbers below the highest one
+	// which are still accepted, if not received before.
+	replayWindowSize = 64
+)
+
+// signingKeyId derives the short key identifier of a public key.
+func signingKeyId(key ed25519.PublicKey) (id [signingKeyIdLen]byte) {
+	sum := sha256.Sum256(key)
+	copy(id[:], sum[:])
+	return
+}
+
+// SignMessage creates a signed message from the payload.
+//
+// The message starts with an identifier of the public key and the sequence
+// number, followed by the payload and the detached Ed25519 signature over all
+// of them. The payload itself is not altered and can still be read by anyone.
+func SignMessage(key ed25519.PrivateKey, seq uint32, payload []byte) []byte {
+	keyId := signingKeyId(key.Public().(ed25519.PublicKey))
+
+	msg := make([]byte, 0, len(payload)+SigningOverhead)
+	msg = append(msg, keyId[:]...)
+	msg = binary.BigEndian.AppendUint32(msg, seq)
+	msg = append(msg, payload...)
+	return append(msg, ed25519.Sign(key, msg)...)
+}
+
+// trustedKey is a named public key within a TrustStore.
+type trustedKey struct {
+	name string
+	key  ed25519.PublicKey
+}
+
+// TrustStore holds the named public keys of trusted peers.
+type TrustStore struct {
+	keys  map[[signingKeyIdLen]byte][]trustedKey
+	mutex sync.RWMutex
+}
+
+// NewTrustStore creates an empty TrustStore.
+func NewTrustStore() *TrustStore {
+	return &TrustStore{keys: make(map[[signingKeyIdLen]byte][]trustedKey)}
+}
+
+// Add a peer's public key under a name, replacing a prior key of this name.
+//
+// This fails for a key which is not ed25519.PublicKeySize bytes long.
+func (store *TrustStore) Add(name string, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key of %s has %d bytes, expected %d", name, len(key), ed25519.PublicKeySize)
+	}
+
+	store.Remove(name)
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	keyId := signingKeyId(key)
+	store.keys[keyId] = append(store.keys[keyId], trustedKey{name, key})
+	return nil
+}
+
+// Remove the public key of the named peer.
+func (store *TrustStore) Remove(name string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for keyId, keys := range store.keys {
+		for i := 0; i < len(keys); i++ {
+			if keys[i].name == name {
+				keys = append(keys[:i], keys[i+1:]...)
+				i--
+			}
+		}
+
+		if len(keys) == 0 {
+			delete(store.keys, keyId)
+		} else {
+			store.keys[keyId] = keys
+		}
+	}
+}
+
+// Verify a message created by SignMessage against the trusted keys.
+//
+// On success, the payload, the name of the signing peer, and the message's
+// sequence number are returned.
+func (store *TrustStore) Verify(msg []byte) (payload []byte, signer string, seq uint32, err error) {
+	if len(msg) < SigningOverhead {
+		err = fmt.Errorf("message of %d bytes is too short to be signed", len(msg))
+		return
+	}
+
+	var keyId [signingKeyIdLen]byte
+	copy(keyId[:], msg)
+	signed, signature := msg[:len(msg)-ed25519.SignatureSize], msg[len(msg)-ed25519.SignatureSize:]
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	for _, trusted := range store.keys[keyId] {
+		if ed25519.Verify(trusted.key, signed, signature) {
+			seq = binary.BigEndian.Uint32(signed[signingKeyIdLen:])
+			return signed[signingKeyIdLen+signingSeqLen:], trusted.name, seq, nil
+		}
+	}
+
+	err = fmt.Errorf("message is not signed by a trusted key %x", keyId)
+	return
+}
+
+// replayPeer is the state of a ReplayWindow for a single peer.
+type replayPeer struct {
+	Highest uint32 `json:"highest"`
+	Bitmap  uint64 `json:"bitmap"`
+}
+
+// ReplayWindow rejects replayed sequence numbers of each peer.
+//
+// For each peer, the highest sequence number is tracked together with a
+// sliding window of the sequence numbers below it. Newer sequence numbers and
+// unseen ones within the window are accepted, all others are rejected.
+type ReplayWindow struct {
+	peers map[string]*replayPeer
+	mutex sync.Mutex
+}
+
+// NewReplayWindow creates a ReplayWindow without any known peers.
+func NewReplayWindow() *ReplayWindow {
+	return &ReplayWindow{peers: make(map[string]*replayPeer)}
+}
+
+// Check if the peer's sequence number is fresh and, if so, record it.
+func (window *ReplayWindow) Check(peer string, seq uint32) bool {
+	window.mutex.Lock()
+	defer window.mutex.Unlock()
+
+	state, ok := window.peers[peer]
+	if !ok {
+		window.peers[peer] = &replayPeer{Highest: seq, Bitmap: 1}
+		return true
+	}
+
+	if seq > state.Highest {
+		if shift := seq - state.Highest; shift >= replayWindowSize {
+			state.Bitmap = 1
+		} else {
+			state.Bitmap = state.Bitmap<<shift | 1
+		}
+		state.Highest = seq
+		return true
+	}
+
+	offset := state.Highest - seq
+	if offset >= replayWindowSize || state.Bitmap&(1<<offset) != 0 {
+		return false
+	}
+
+	state.Bitmap |= 1 << offset
+	return true
+}
+
+// signingState is the persisted state of a Signing, see SaveState.
+type signingState struct {
+	Seq   uint32                 `json:"seq"`
+	Peers map[string]*replayPeer `json:"peers"`
+}
+
+// Signing sends signed messages over a Modem and verifies received ones.
+//
+// Received messages with a valid signature of a trusted peer are passed to the
+// RX handler together with the peer's name. All other messages are dropped,
+// including replayed ones which were already received before.
+//
+// The own sequence number and the peers' replay windows should be persisted
+// across restarts by SaveState and LoadState. Otherwise, peers will reject
+// messages until the sequence number exceeds the one before the restart.
+type Signing struct {
+	modem  *Modem
+	key    ed25519.PrivateKey
+	store  *TrustStore
+	replay *ReplayWindow
+
+	rxHandler func(RxMessage, string)
+
+	// seq and mtu are protected through sync/atomic calls.
+	seq uint32
+	mtu int32
+}
+
+// NewSigning backed by the given Modem, signing with the private key.
+//
+// This function registers itself with its handler functions at the Modem. It
+// fails if the Modem's MTU leaves no room for a payload next to the signature.
+func NewSigning(modem *Modem, key ed25519.PrivateKey, store *TrustStore, rxHandler func(RxMessage, string)) (*Signing, error) {
+	s := &Signing{
+		modem:     modem,
+		key:       key,
+		store:     store,
+		replay:    NewReplayWindow(),
+		rxHandler: rxHandler,
+	}
+
+	if err := modem.refreshMtu(); err != nil {
+		return nil, err
+	} else if _, err := PayloadBudget(modem.Mtu(), s); err != nil {
+		return nil, err
+	}
+
+	if _, err := modem.RegisterHandlers(s.handleRx, s.handleMtu); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// handleRx is the rxHandler being passed to the Modem.
+func (signing *Signing) handleRx(rx RxMessage) {
+	if signing.rxHandler == nil {
+		return
+	}
+
+	payload, signer, seq, err := signing.store.Verify(rx.Payload)
+	if err != nil || !signing.replay.Check(signer, seq) {
+		return
+	}
+
+	rx.Payload = payload
+	signing.rxHandler(rx, signer)
+}
+
+// handleMtu is the mtuHandler passed to the Modem.
+func (signing *Signing) handleMtu(mtu int) {
+	atomic.StoreInt32(&signing.mtu, int32(mtu))
+}
+
+// Overhead of the signature in bytes, to implement Layer.
+func (signing *Signing) Overhead() int {
+	return SigningOverhead
+}
+
+// Mtu returns the maximum payload length left after the signing overhead.
+func (signing *Signing) Mtu() int {
+	return int(atomic.LoadInt32(&signing.mtu)) - SigningOverhead
+}
+
+// Transmit the signed byte array whose length must not exceed Mtu.
+//
+// The returned length refers to the payload, excluding the signing overhead.
+func (signing *Signing) Transmit(p []byte) (int, error) {
+	if mtu := signing.Mtu(); len(p) > mtu {
+		return 0, fmt.Errorf("payload of %d bytes exceeds the signed MTU of %d bytes", len(p), mtu)
+	}
+
+	seq := atomic.AddUint32(&signing.seq, 1)
+	n, err := signing.modem.Transmit(SignMessage(signing.key, seq, p))
+	if n -= SigningOverhead; n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+// SaveState writes the own sequence number and the peers' replay windows as JSON.
+func (signing *Signing) SaveState(w io.Writer) error {
+	signing.replay.mutex.Lock()
+	defer signing.replay.mutex.Unlock()
+
+	return json.NewEncoder(w).Encode(signingState{
+		Seq:   atomic.LoadUint32(&signing.seq),
+		Peers: signing.replay.peers,
+	})
+}
+
+// LoadState restores a state written by SaveState.
+func (signing *Signing) LoadState(r io.Reader) error {
+	state := signingState{Peers: make(map[string]*replayPeer)}
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+
+	if state.Peers == nil {
+		state.Peers = make(map[string]*replayPeer)
+	}
+
+	signing.replay.mutex.Lock()
+	defer signing.replay.mutex.Unlock()
+
+	atomic.StoreUint32(&signing.seq, state.Seq)
+	signing.replay.peers = state.Peers
+	return nil
+}
@@ -0,0 +1,154 @@
+package rf95
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTrustStoreVerify(t *testing.T) {
+	alicePub, alicePriv, _ := ed25519.GenerateKey(nil)
+	_, malloryPriv, _ := ed25519.GenerateKey(nil)
+
+	store := NewTrustStore()
+	if err := store.Add("alice", alicePub); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add("mallory", alicePub[:16]); err == nil {
+		t.Fatalf("adding a truncated key did not error")
+	}
+
+	payload := []byte("hello world")
+
+	if p, signer, seq, err := store.Verify(SignMessage(alicePriv, 23, payload)); err != nil {
+		t.Fatalf("verifying alice's message errored: %v", err)
+	} else if signer != "alice" {
+		t.Fatalf("signer is %s, expected alice", signer)
+	} else if seq != 23 {
+		t.Fatalf("sequence number is %d, expected 23", seq)
+	} else if !bytes.Equal(p, payload) {
+		t.Fatalf("payload is %x, expected %x", p, payload)
+	}
+
+	if _, _, _, err := store.Verify(SignMessage(malloryPriv, 1, payload)); err == nil {
+		t.Fatalf("verifying mallory's message did not error")
+	}
+
+	tampered := SignMessage(alicePriv, 1, payload)
+	tampered[signingKeyIdLen+signingSeqLen] ^= 0xFF
+	if _, _, _, err := store.Verify(tampered); err == nil {
+		t.Fatalf("verifying a tampered message did not error")
+	}
+
+	if _, _, _, err := store.Verify([]byte{0x23, 0x42}); err == nil {
+		t.Fatalf("verifying a short message did not error")
+	}
+
+	store.Remove("alice")
+	if _, _, _, err := store.Verify(SignMessage(alicePriv, 1, payload)); err == nil {
+		t.Fatalf("verifying a message of a removed key did not error")
+	}
+}
+
+func TestReplayWindowCheck(t *testing.T) {
+	window := NewReplayWindow()
+
+	tests := []struct {
+		peer  string
+		seq   uint32
+		fresh bool
+	}{
+		{"alice", 10, true},
+		{"alice", 10, false},
+		{"alice", 12, true},
+		{"alice", 11, true},
+		{"alice", 11, false},
+		{"bob", 11, true},
+		{"alice", 100, true},
+		{"alice", 37, true},
+		{"alice", 36, false},
+		{"alice", 12, false},
+		{"alice", 37, false},
+	}
+
+	for _, test := range tests {
+		if fresh := window.Check(test.peer, test.seq); fresh != test.fresh {
+			t.Fatalf("checking %s's sequence number %d returned %t, expected %t",
+				test.peer, test.seq, fresh, test.fresh)
+		}
+	}
+}
+
+func TestSigningState(t *testing.T) {
+	alicePub, alicePriv, _ := ed25519.GenerateKey(nil)
+	_, bobPriv, _ := ed25519.GenerateKey(nil)
+
+	store := NewTrustStore()
+	if err := store.Add("alice", alicePub); err != nil {
+		t.Fatal(err)
+	}
+
+	// newBob creates a Signing of bob, reporting received payloads and transmitted messages.
+	newBob := func() (*Signing, *testDevice, chan []byte, chan []byte) {
+		txMsgs := make(chan []byte, 1)
+		modem, dev := newTestModem(t, func(cmd string) []string {
+			if strings.HasPrefix(cmd, "AT+TX=") {
+				msg, _ := hex.DecodeString(strings.TrimPrefix(cmd, "AT+TX="))
+				txMsgs <- msg
+				return []string{fmt.Sprintf("+SENT %d bytes.\n", len(msg))}
+			}
+			return nil
+		})
+
+		rxPayloads := make(chan []byte, 2)
+		signing, err := NewSigning(modem, bobPriv, store, func(rx RxMessage, _ string) { rxPayloads <- rx.Payload })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signing, dev, rxPayloads, txMsgs
+	}
+
+	injectAlice := func(dev *testDevice, seq uint32, payload string) {
+		msg := SignMessage(alicePriv, seq, []byte(payload))
+		dev.inject(fmt.Sprintf("+RX %d,%X,-15,8\n", len(msg), msg))
+	}
+
+	signing, dev, rxPayloads, txMsgs := newBob()
+
+	injectAlice(dev, 5, "first")
+	if p := <-rxPayloads; string(p) != "first" {
+		t.Fatalf("received %q, expected first", p)
+	}
+	if _, err := signing.Transmit([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	<-txMsgs
+
+	var state bytes.Buffer
+	if err := signing.SaveState(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a restart, alice's replayed message is rejected and bob's sequence continues.
+	restarted, dev, rxPayloads, txMsgs := newBob()
+	if err := restarted.LoadState(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	injectAlice(dev, 5, "replayed")
+	injectAlice(dev, 6, "second")
+	if p := <-rxPayloads; string(p) != "second" {
+		t.Fatalf("received %q, expected the replayed message to be rejected", p)
+	}
+
+	if _, err := restarted.Transmit([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-txMsgs; binary.BigEndian.Uint32(msg[signingKeyIdLen:]) != 2 {
+		t.Fatalf("sent sequence number %d, expected 2", binary.BigEndian.Uint32(msg[signingKeyIdLen:]))
+	}
+}
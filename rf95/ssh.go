@@ -0,0 +1,81 @@
+package rf95
+
+import (
+	"context"
+	"io"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshConn attaches to the standard input and output of a remote command.
+type sshConn struct {
+	client  *ssh.Client
+	session *ssh.Session
+
+	stdin  io.WriteCloser
+	stdout io.Reader
+}
+
+// OpenSSH creates a new Modem attached to a remote command's standard input and output over SSH.
+//
+// The remote command must pass the rf95modem's stream through, e.g.,
+// "socat - /dev/ttyUSB0,b115200,raw,echo=0". The ssh.ClientConfig specifies
+// the user's authentication and the host key verification, e.g., by
+// golang.org/x/crypto/ssh/knownhosts. As the Modem is based on a Transport, a
+// ReconnectPolicy reconnects the session; without one, the Modem finishes when
+// the remote command exits. For Context information, check
+// OpenModem's documentation; the Context also bounds the connection's
+// establishment.
+func OpenSSH(address string, config *ssh.ClientConfig, command string, ctx context.Context) (*Modem, error) {
+	return OpenTransport(NewTransport(func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return dialSSH(address, config, command, ctx)
+	}), ctx)
+}
+
+// dialSSH connects to the address and starts the remote command.
+func dialSSH(address string, config *ssh.ClientConfig, command string, ctx context.Context) (c *sshConn, err error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return
+	}
+
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	c = &sshConn{client: ssh.NewClient(clientConn, chans, reqs)}
+	defer func() {
+		if err != nil {
+			_ = c.client.Close()
+			c = nil
+		}
+	}()
+
+	if c.session, err = c.client.NewSession(); err != nil {
+		return
+	} else if c.stdin, err = c.session.StdinPipe(); err != nil {
+		return
+	} else if c.stdout, err = c.session.StdoutPipe(); err != nil {
+		return
+	}
+
+	err = c.session.Start(command)
+	return
+}
+
+func (c *sshConn) Read(p []byte) (int, error) {
+	return c.stdout.Read(p)
+}
+
+func (c *sshConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *sshConn) Close() error {
+	_ = c.session.Close()
+	return c.client.Close()
+}
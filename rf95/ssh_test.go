@@ -0,0 +1,134 @@
+package rf95
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// serveTestSsh accepts SSH sessions and answers AT+INFO for the remote command "rf95modem".
+//
+// The remote command exits when the exit channel is closed.
+func serveTestSsh(listener net.Listener, config *ssh.ServerConfig, exit <-chan struct{}) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			_, chans, reqs, err := ssh.NewServerConn(conn, config)
+			if err != nil {
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+
+			for newChan := range chans {
+				channel, chanReqs, err := newChan.Accept()
+				if err != nil {
+					return
+				}
+
+				go func() {
+					for req := range chanReqs {
+						// The exec payload is the command, prefixed by its length.
+						ok := req.Type == "exec" && string(req.Payload[4:]) == "rf95modem"
+						_ = req.Reply(ok, nil)
+					}
+				}()
+
+				go func() {
+					<-exit
+					_ = channel.Close()
+				}()
+
+				go func() {
+					defer channel.Close()
+
+					reader := bufio.NewReader(channel)
+					for {
+						line, err := reader.ReadString('\n')
+						if err != nil {
+							return
+						}
+						if strings.TrimSpace(line) == "AT+INFO" {
+							_, _ = channel.Write([]byte(strings.Join(testInfo, "")))
+						}
+					}
+				}()
+			}
+		}()
+	}
+}
+
+func TestOpenSSH(t *testing.T) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "rf95" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	exit := make(chan struct{})
+	go serveTestSsh(listener, serverConfig, exit)
+
+	// An unknown host key must be refused.
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(otherKey)
+	if _, err := OpenSSH(listener.Addr().String(), &ssh.ClientConfig{
+		User:            "rf95",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.FixedHostKey(otherSigner.PublicKey()),
+	}, "rf95modem", context.Background()); err == nil {
+		t.Fatal("connection to an unknown host key succeeded")
+	}
+
+	modem, err := OpenSSH(listener.Addr().String(), &ssh.ClientConfig{
+		User:            "rf95",
+		Auth:            []ssh.AuthMethod{ssh.Password("secret")},
+		HostKeyCallback: ssh.FixedHostKey(hostSigner.PublicKey()),
+	}, "rf95modem", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+
+	// Without a ReconnectPolicy, the Modem finishes when the remote command exits.
+	close(exit)
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("modem is not finished after the remote command exited")
+	}
+}
@@ -0,0 +1,101 @@
+package rf95
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// State is the persistable configuration of a Modem.
+//
+// It is created by ExportState and restored by ImportState, e.g., to set up
+// replacement hardware identically.
+//
+// TxPower is the transmit power in dBm, zero if the firmware does not report
+// it. Otherwise, it is restored as well, as it is subject to regulation.
+type State struct {
+	Mode      ModemMode `json:"mode"`
+	Frequency float64   `json:"frequency"`
+	TxPower   int       `json:"tx_power,omitempty"`
+}
+
+// ExportState writes the rf95modem's current configuration as JSON.
+func (modem *Modem) ExportState(w io.Writer) error {
+	status, err := modem.FetchStatus()
+	if err != nil {
+		return err
+	}
+
+	state := State{
+		Mode:      status.Mode,
+		Frequency: status.Frequency,
+		TxPower:   status.TxPower,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(state)
+}
+
+// ImportState reads a configuration, written by ExportState, and applies it.
+//
+// The whole configuration is validated before anything is written to the
+// rf95modem. This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) ImportState(r io.Reader) error {
+	var state State
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+
+	if err := state.check(); err != nil {
+		return err
+	}
+
+	return modem.withLease(nil, func() error { return modem.applyState(&state) })
+}
+
+// DryRunState validates a change to the State without applying it, like DryRun.
+//
+// A non-zero TxPower is additionally checked against its range and the
+// rf95modem's Capabilities, as older firmware builds cannot set it. Such
+// violations are added to the ConfigReport's Violations.
+func (modem *Modem) DryRunState(state State) (report ConfigReport, err error) {
+	if report, err = modem.DryRun(state.Mode, state.Frequency); err != nil || state.TxPower == 0 {
+		return
+	}
+
+	report.TxPower = state.TxPower
+	if checkErr := checkTxPower(state.TxPower); checkErr != nil {
+		report.Violations = append(report.Violations, checkErr)
+	}
+
+	capabilities, err := modem.Capabilities()
+	if err != nil {
+		return
+	} else if !capabilities.Has(CapabilityTxPower) {
+		report.Violations = append(report.Violations, fmt.Errorf("firmware %s cannot set the transmit power", report.Current.Firmware))
+	}
+	return
+}
+
+// check the State's values before applying them.
+func (state *State) check() error {
+	if err := checkFrequency(state.Frequency); err != nil {
+		return err
+	} else if err := checkMode(state.Mode); err != nil {
+		return err
+	} else if state.TxPower != 0 {
+		return checkTxPower(state.TxPower)
+	}
+	return nil
+}
+
+// applyState without respecting a ConfigLease.
+func (modem *Modem) applyState(state *State) error {
+	if err := modem.configure(state.Frequency, state.Mode); err != nil {
+		return err
+	} else if state.TxPower != 0 {
+		return modem.txPower(state.TxPower)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package rf95
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestModemStateRoundTrip(t *testing.T) {
+	source, _ := newTestModem(t, nil)
+
+	var state bytes.Buffer
+	if err := source.ExportState(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	var cmds []string
+	target, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		switch {
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			return []string{"+FREQ: 868.10\n"}
+		case strings.HasPrefix(cmd, "AT+MODE="):
+			return []string{"+OK\n"}
+		default:
+			return nil
+		}
+	})
+
+	if err := target.ImportState(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"AT+FREQ=868.10", "AT+MODE=0", "AT+INFO"}; !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("commands are %v, expected %v", cmds, expected)
+	}
+}
+
+func TestModemImportStateInvalid(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		return nil
+	})
+
+	tests := []string{
+		`{"mode": 23, "frequency": 868.1}`,
+		`{"mode": 0, "frequency": 23.42}`,
+		`{"mode": 0, "frequency": 868.1, "tx_power": 42}`,
+		`{"mode": "fast"}`,
+	}
+
+	for _, test := range tests {
+		if err := modem.ImportState(strings.NewReader(test)); err == nil {
+			t.Fatalf("importing %s did not error", test)
+		}
+	}
+
+	if len(cmds) != 0 {
+		t.Fatalf("invalid states sent %v, expected nothing", cmds)
+	}
+}
@@ -79,11 +79,33 @@ func (stream *Stream) Read(p []byte) (int, error) {
 	}
 }
 
+// Progress of a write, reported by WriteProgress after each packet.
+type Progress struct {
+	// Sent and Total are the bytes transmitted so far and overall.
+	Sent  int
+	Total int
+
+	// Packets is the number of packets transmitted so far.
+	Packets int
+
+	// ETA estimates the remaining time by the airtime of the packets left. It is
+	// a lower bound, ignoring the serial link, and zero for an unknown ModemMode.
+	ETA time.Duration
+}
+
 // Write the byte array to the rf95modem.
 //
 // If its length exceeds the MTU, multiple packets will be send.
 func (stream *Stream) Write(p []byte) (n int, err error) {
-	for pos := 0; pos < len(p); {
+	return stream.WriteProgress(p, nil)
+}
+
+// WriteProgress writes the byte array like Write and reports its Progress after each packet.
+//
+// The progress function might be nil. It is called synchronously, so it should
+// return quickly, e.g., after updating a progress bar.
+func (stream *Stream) WriteProgress(p []byte, progress func(Progress)) (n int, err error) {
+	for pos, packets := 0, 0; pos < len(p); {
 		mtu := int(atomic.LoadInt32(&stream.mtu))
 
 		bound := pos + mtu
@@ -99,7 +121,34 @@ func (stream *Stream) Write(p []byte) (n int, err error) {
 		}
 
 		pos += mtu
+		packets++
+
+		if progress != nil {
+			progress(Progress{
+				Sent:    n,
+				Total:   len(p),
+				Packets: packets,
+				ETA:     stream.eta(len(p)-n, mtu),
+			})
+		}
 	}
 
 	return
 }
+
+// eta estimates the airtime for the remaining bytes, split into packets of the MTU.
+func (stream *Stream) eta(remaining, mtu int) (eta time.Duration) {
+	stream.modem.devMutex.Lock()
+	state := stream.modem.knownState
+	stream.modem.devMutex.Unlock()
+
+	if state == nil || remaining <= 0 || mtu <= 0 {
+		return
+	}
+
+	eta = time.Duration(remaining/mtu) * state.Mode.Airtime(mtu)
+	if rest := remaining % mtu; rest > 0 {
+		eta += state.Mode.Airtime(rest)
+	}
+	return
+}
@@ -0,0 +1,44 @@
+package rf95
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStreamWriteProgress(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+TX=") {
+			payload, _ := hex.DecodeString(strings.TrimPrefix(cmd, "AT+TX="))
+			return []string{fmt.Sprintf("+SENT %d bytes.\n", len(payload))}
+		}
+		return nil
+	})
+
+	stream, err := NewStream(modem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []Progress
+	if n, err := stream.WriteProgress(make([]byte, 600), func(p Progress) { reports = append(reports, p) }); err != nil {
+		t.Fatal(err)
+	} else if n != 600 {
+		t.Fatalf("wrote %d bytes, expected 600", n)
+	}
+
+	expected := []Progress{
+		{Sent: 251, Total: 600, Packets: 1, ETA: MediumRange.Airtime(251) + MediumRange.Airtime(98)},
+		{Sent: 502, Total: 600, Packets: 2, ETA: MediumRange.Airtime(98)},
+		{Sent: 600, Total: 600, Packets: 3, ETA: 0},
+	}
+	if len(reports) != len(expected) {
+		t.Fatalf("got %d progress reports, expected %d", len(reports), len(expected))
+	}
+	for i := range expected {
+		if reports[i] != expected[i] {
+			t.Fatalf("progress report %d is %v, expected %v", i, reports[i], expected[i])
+		}
+	}
+}
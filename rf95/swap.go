@@ -0,0 +1,66 @@
+package rf95
+
+import (
+	"fmt"
+	"io"
+)
+
+// SwapTransport replaces the Modem's device at runtime, e.g., to migrate from a
+// serial to a network link or to a replacement board.
+//
+// All registered handlers, and thus Streams and other layers, stay intact. A
+// running AT command is completed first. The current device must have an
+// io.Closer, as closing it interrupts the worker's read. Afterwards, the MTU
+// is refreshed from the new device and the catalog of its AT commands is
+// queried anew; its configuration is not changed. The new device is handled as
+// by OpenModem, e.g., without a baud rate and finishing on EOF.
+func (modem *Modem) SwapTransport(r io.Reader, w io.Writer, c io.Closer) error {
+	return modem.SwapTransportConfig(r, w, c, ModemConfig{})
+}
+
+// SwapTransportConfig replaces the Modem's device at runtime, described by the ModemConfig.
+//
+// The ModemConfig's Baud and RetryEOF apply to the new device. Its Profile is
+// ignored, as the Modem keeps its buffers. For details, check SwapTransport's
+// documentation.
+func (modem *Modem) SwapTransportConfig(r io.Reader, w io.Writer, c io.Closer, config ModemConfig) error {
+	modem.atCommandMutex.Lock()
+
+	modem.devMutex.Lock()
+	oldCloser := modem.devCloser
+	if oldCloser == nil || modem.devClosed {
+		modem.devMutex.Unlock()
+		modem.atCommandMutex.Unlock()
+		return fmt.Errorf("current device cannot be closed to be swapped")
+	}
+
+	swapDone := make(chan struct{})
+	modem.swapReader, modem.swapDone = r, swapDone
+	modem.devReader, modem.devWriter, modem.devCloser = r, w, c
+	modem.baud, modem.retryEOF = config.Baud, config.RetryEOF
+	modem.transport = nil
+	modem.devMutex.Unlock()
+
+	_ = oldCloser.Close()
+
+	select {
+	case <-swapDone:
+	case <-modem.ctx.Done():
+		modem.atCommandMutex.Unlock()
+		return io.EOF
+	}
+
+	// Drop lines of the old device, which nobody waits for anymore.
+	for drained := false; !drained; {
+		select {
+		case <-modem.msgQueue:
+		default:
+			drained = true
+		}
+	}
+
+	modem.atCommandMutex.Unlock()
+
+	modem.forgetCommands()
+	return modem.refreshMtu()
+}
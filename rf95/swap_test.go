@@ -0,0 +1,80 @@
+package rf95
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestModemSwapTransport(t *testing.T) {
+	modem, _ := newTestModem(t, nil)
+
+	rxChan := make(chan RxMessage, 1)
+	mtuChan := make(chan int, 2)
+	if _, err := modem.RegisterHandlers(func(rx RxMessage) { rxChan <- rx }, func(mtu int) { mtuChan <- mtu }); err != nil {
+		t.Fatal(err)
+	}
+	<-mtuChan
+
+	// The replacement board supports smaller packets.
+	newDev := &testDevice{respond: func(cmd string) []string {
+		if cmd == "AT+INFO" {
+			info := append([]string(nil), testInfo...)
+			info[5] = "max pkt size:  128\n"
+			return info
+		}
+		return []string{"+FAIL\n"}
+	}}
+	newDev.pipeReader, newDev.pipeWriter = io.Pipe()
+
+	if err := modem.SwapTransport(newDev.pipeReader, newDev, newDev); err != nil {
+		t.Fatal(err)
+	}
+
+	if mtu := <-mtuChan; mtu != 128 {
+		t.Fatalf("MTU handler got %d, expected 128", mtu)
+	}
+
+	newDev.inject("+RX 3,414141,-15,8\n")
+	if rx := <-rxChan; string(rx.Payload) != "AAA" {
+		t.Fatalf("received payload %q, expected AAA", rx.Payload)
+	}
+
+	// Without a Closer, the device cannot be swapped again.
+	closerlessDev := &testDevice{respond: newDev.respond}
+	closerlessDev.pipeReader, closerlessDev.pipeWriter = io.Pipe()
+
+	if err := modem.SwapTransport(closerlessDev.pipeReader, closerlessDev, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := modem.SwapTransport(newDev.pipeReader, newDev, newDev); err == nil {
+		t.Fatal("swapping a device without a Closer succeeded")
+	}
+}
+
+func TestModemSwapTransportConfig(t *testing.T) {
+	modem, _ := newTestModemConfig(t, ModemConfig{Baud: 115200, RetryEOF: true}, nil)
+
+	newDev := &testDevice{respond: func(cmd string) []string { return testInfo }}
+	newDev.pipeReader, newDev.pipeWriter = io.Pipe()
+
+	if err := modem.SwapTransport(newDev.pipeReader, newDev, newDev); err != nil {
+		t.Fatal(err)
+	}
+
+	modem.devMutex.Lock()
+	baud, retryEOF := modem.baud, modem.retryEOF
+	modem.devMutex.Unlock()
+	if baud != 0 || retryEOF {
+		t.Fatalf("swapped device has baud rate %d and RetryEOF %t, expected the zero ModemConfig", baud, retryEOF)
+	}
+
+	// The new device's EOF finishes the Modem instead of being retried.
+	_ = newDev.Close()
+
+	select {
+	case <-modem.ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("modem is not finished after the swapped device's EOF")
+	}
+}
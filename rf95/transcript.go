@@ -0,0 +1,93 @@
+package rf95
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// transcriptSize is the default number of lines kept in a Modem's transcript.
+const transcriptSize = 256
+
+// Direction of a TranscriptLine, seen from the host.
+type Direction int
+
+const (
+	// Sent lines were written to the rf95modem.
+	Sent Direction = iota
+
+	// Received lines were read from the rf95modem.
+	Received
+)
+
+func (d Direction) String() string {
+	if d == Sent {
+		return ">"
+	}
+	return "<"
+}
+
+// TranscriptLine is a raw line exchanged with the rf95modem.
+type TranscriptLine struct {
+	Time      time.Time
+	Direction Direction
+	Line      string
+}
+
+func (line TranscriptLine) String() string {
+	return fmt.Sprintf("%s %v %s",
+		line.Time.Format(time.RFC3339Nano), line.Direction, strings.TrimRight(line.Line, "\r\n"))
+}
+
+// transcript is a ring buffer of the most recent TranscriptLines.
+type transcript struct {
+	lines []TranscriptLine
+	next  int
+	full  bool
+	mutex sync.Mutex
+}
+
+// newTranscript for up to size lines.
+func newTranscript(size int) *transcript {
+	return &transcript{lines: make([]TranscriptLine, size)}
+}
+
+// record a line, possibly overwriting the oldest one.
+func (t *transcript) record(direction Direction, line string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.lines) == 0 {
+		return
+	}
+
+	t.lines[t.next] = TranscriptLine{time.Now(), direction, line}
+	t.next = (t.next + 1) % len(t.lines)
+	t.full = t.full || t.next == 0
+}
+
+// snapshot of all recorded lines, from oldest to newest.
+func (t *transcript) snapshot() []TranscriptLine {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.full {
+		return append([]TranscriptLine(nil), t.lines[:t.next]...)
+	}
+	return append(append([]TranscriptLine(nil), t.lines[t.next:]...), t.lines[:t.next]...)
+}
+
+// Transcript returns the most recent raw lines exchanged with the rf95modem, from oldest to newest.
+//
+// The Modem always keeps a bounded transcript, which helps diagnosing
+// transient errors after the fact without having enabled any logging.
+func (modem *Modem) Transcript() []TranscriptLine {
+	return modem.transcript.snapshot()
+}
+
+// DumpTranscript writes the Transcript, one line per TranscriptLine.
+func (modem *Modem) DumpTranscript(w io.Writer) error {
+	return WriteSession(w, modem.Transcript())
+}
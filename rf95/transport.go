@@ -0,0 +1,179 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Transport is a link to a rf95modem, e.g., a serial device or a network connection.
+//
+// Implementations allow plugging in other link layers, e.g., RS485 converters
+// or bridges, through OpenTransport. Read, Write, and Close might be called
+// concurrently to Redial and must therefore be synchronized.
+type Transport interface {
+	io.ReadWriteCloser
+
+	// Dial establishes the link initially.
+	Dial(ctx context.Context) error
+
+	// Redial establishes the link again, e.g., after an I/O error. This must
+	// also work after Close.
+	Redial(ctx context.Context) error
+}
+
+// ControlLines are the serial control lines of a Transport, e.g., to reset a board.
+type ControlLines interface {
+	// SetDtr asserts or clears the DTR line.
+	SetDtr(bool) error
+
+	// SetRts asserts or clears the RTS line.
+	SetRts(bool) error
+}
+
+// OpenTransport creates a new Modem based on a Transport, which is dialed first.
+//
+// If a ReconnectPolicy without an Open function is set, the Transport will be
+// redialed. For Context information, check OpenModem's documentation.
+func OpenTransport(transport Transport, ctx context.Context) (*Modem, error) {
+	return OpenTransportConfig(transport, ModemConfig{}, ctx)
+}
+
+// OpenTransportConfig creates a new Modem based on a Transport, configured by the ModemConfig.
+//
+// For details, check OpenTransport's documentation.
+func OpenTransportConfig(transport Transport, config ModemConfig, ctx context.Context) (*Modem, error) {
+	if err := transport.Dial(ctx); err != nil {
+		return nil, err
+	}
+
+	modem, err := OpenModemConfig(transport, transport, transport, config, ctx)
+	if err != nil {
+		return nil, err
+	}
+	modem.transport = transport
+
+	return modem, nil
+}
+
+// dialTransport is a Transport established by a dial function.
+type dialTransport struct {
+	dial func(context.Context) (io.ReadWriteCloser, error)
+
+	conn      io.ReadWriteCloser
+	connMutex sync.RWMutex
+}
+
+// NewTransport creates a Transport which establishes its link by the dial function, e.g., SerialOpener.
+func NewTransport(dial func(context.Context) (io.ReadWriteCloser, error)) Transport {
+	return &dialTransport{dial: dial}
+}
+
+func (transport *dialTransport) Dial(ctx context.Context) error {
+	return transport.Redial(ctx)
+}
+
+func (transport *dialTransport) Redial(ctx context.Context) error {
+	_ = transport.Close()
+
+	conn, err := transport.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	transport.connMutex.Lock()
+	transport.conn = conn
+	transport.connMutex.Unlock()
+	return nil
+}
+
+// current connection or an error if there is none.
+func (transport *dialTransport) current() (io.ReadWriteCloser, error) {
+	transport.connMutex.RLock()
+	defer transport.connMutex.RUnlock()
+
+	if transport.conn == nil {
+		return nil, io.ErrClosedPipe
+	}
+	return transport.conn, nil
+}
+
+func (transport *dialTransport) Read(p []byte) (int, error) {
+	conn, err := transport.current()
+	if err != nil {
+		return 0, err
+	}
+	return conn.Read(p)
+}
+
+func (transport *dialTransport) Write(p []byte) (int, error) {
+	conn, err := transport.current()
+	if err != nil {
+		return 0, err
+	}
+	return conn.Write(p)
+}
+
+func (transport *dialTransport) Close() error {
+	transport.connMutex.Lock()
+	conn := transport.conn
+	transport.conn = nil
+	transport.connMutex.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+func (transport *dialTransport) SetDtr(dtr bool) error {
+	return transport.control(func(lines ControlLines) error { return lines.SetDtr(dtr) })
+}
+
+func (transport *dialTransport) SetRts(rts bool) error {
+	return transport.control(func(lines ControlLines) error { return lines.SetRts(rts) })
+}
+
+// control applies the function to the current connection's ControlLines, if supported.
+func (transport *dialTransport) control(f func(ControlLines) error) error {
+	conn, err := transport.current()
+	if err != nil {
+		return err
+	}
+
+	lines, ok := conn.(ControlLines)
+	if !ok {
+		return fmt.Errorf("connection does not support control lines")
+	}
+	return f(lines)
+}
+
+// SetDtr asserts or clears the DTR line, if the Modem's Transport supports ControlLines.
+func (modem *Modem) SetDtr(dtr bool) error {
+	lines, err := modem.controlLines()
+	if err != nil {
+		return err
+	}
+	return lines.SetDtr(dtr)
+}
+
+// SetRts asserts or clears the RTS line, if the Modem's Transport supports ControlLines.
+func (modem *Modem) SetRts(rts bool) error {
+	lines, err := modem.controlLines()
+	if err != nil {
+		return err
+	}
+	return lines.SetRts(rts)
+}
+
+// controlLines of the current device.
+func (modem *Modem) controlLines() (ControlLines, error) {
+	modem.devMutex.Lock()
+	defer modem.devMutex.Unlock()
+
+	if lines, ok := modem.devWriter.(ControlLines); ok {
+		return lines, nil
+	}
+	return nil, fmt.Errorf("device does not support control lines")
+}
@@ -0,0 +1,101 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTransportRedial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rf95modem.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Drop the first connection right away and serve the next ones.
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.Close()
+		}
+		serveTestModem(listener)
+	}()
+
+	modem, err := OpenUnix(path, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	reconnectChan := make(chan error, 1)
+	modem.SetReconnectPolicy(&ReconnectPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		OnReconnect:    func(err error) { reconnectChan <- err },
+	})
+
+	select {
+	case err := <-reconnectChan:
+		if err != nil {
+			t.Fatalf("reconnect reported %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Transport was not redialed")
+	}
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.Mtu != 251 {
+		t.Fatalf("status has MTU %d, expected 251", status.Mtu)
+	}
+}
+
+// testControlDevice is a testDevice recording changes of its ControlLines.
+type testControlDevice struct {
+	*testDevice
+	lines []string
+}
+
+func (dev *testControlDevice) SetDtr(dtr bool) error {
+	dev.lines = append(dev.lines, fmt.Sprintf("DTR=%t", dtr))
+	return nil
+}
+
+func (dev *testControlDevice) SetRts(rts bool) error {
+	dev.lines = append(dev.lines, fmt.Sprintf("RTS=%t", rts))
+	return nil
+}
+
+func TestModemControlLines(t *testing.T) {
+	dev := &testControlDevice{testDevice: &testDevice{respond: func(string) []string { return nil }}}
+	dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+	modem, err := OpenTransport(NewTransport(func(context.Context) (io.ReadWriteCloser, error) {
+		return dev, nil
+	}), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if err := modem.SetDtr(false); err != nil {
+		t.Fatal(err)
+	} else if err := modem.SetRts(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"DTR=false", "RTS=true"}; !reflect.DeepEqual(dev.lines, expected) {
+		t.Fatalf("control lines were set to %v, expected %v", dev.lines, expected)
+	}
+
+	plainModem, _ := newTestModem(t, nil)
+	if err := plainModem.SetDtr(true); err == nil {
+		t.Fatal("setting DTR of a device without control lines succeeded")
+	}
+}
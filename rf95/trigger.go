@@ -0,0 +1,86 @@
+package rf95
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Trigger captures the traffic surrounding a matching RxMessage, like an oscilloscope.
+//
+// The last RxMessages are kept in a pre-trigger ring. When a message matches,
+// the ring, the matching message and all messages within the post-trigger
+// window are passed to the capture function. Matches during a running capture
+// do not start another one. HandleRx might be registered as an RX handler.
+type Trigger struct {
+	match   func(RxMessage) bool
+	post    time.Duration
+	capture func([]RxMessage)
+
+	mutex     sync.Mutex
+	ring      []RxMessage
+	ringPos   int
+	ringFull  bool
+	capturing []RxMessage
+	active    bool
+}
+
+// NewTrigger keeping pre messages before a match and capturing for the post window afterwards.
+//
+// The number of pre messages must not be negative; zero disables the pre-trigger ring.
+func NewTrigger(match func(RxMessage) bool, pre int, post time.Duration, capture func([]RxMessage)) (*Trigger, error) {
+	if pre < 0 {
+		return nil, fmt.Errorf("pre-trigger size %d must not be negative", pre)
+	}
+
+	return &Trigger{
+		match:   match,
+		post:    post,
+		capture: capture,
+		ring:    make([]RxMessage, pre),
+	}, nil
+}
+
+// HandleRx records a received message and checks it against the trigger's match function.
+func (trigger *Trigger) HandleRx(rx RxMessage) {
+	trigger.mutex.Lock()
+	defer trigger.mutex.Unlock()
+
+	if trigger.active {
+		trigger.capturing = append(trigger.capturing, rx)
+		return
+	}
+
+	if !trigger.match(rx) {
+		if len(trigger.ring) > 0 {
+			trigger.ring[trigger.ringPos] = rx
+			trigger.ringPos = (trigger.ringPos + 1) % len(trigger.ring)
+			trigger.ringFull = trigger.ringFull || trigger.ringPos == 0
+		}
+		return
+	}
+
+	trigger.capturing = append(trigger.pretrigger(), rx)
+	trigger.active = true
+	trigger.ringPos, trigger.ringFull = 0, false
+
+	time.AfterFunc(trigger.post, trigger.finish)
+}
+
+// pretrigger returns the ring's messages in order of their reception.
+func (trigger *Trigger) pretrigger() []RxMessage {
+	if !trigger.ringFull {
+		return append([]RxMessage(nil), trigger.ring[:trigger.ringPos]...)
+	}
+	return append(append([]RxMessage(nil), trigger.ring[trigger.ringPos:]...), trigger.ring[:trigger.ringPos]...)
+}
+
+// finish the running capture after the post-trigger window and pass it on.
+func (trigger *Trigger) finish() {
+	trigger.mutex.Lock()
+	capture := trigger.capturing
+	trigger.capturing, trigger.active = nil, false
+	trigger.mutex.Unlock()
+
+	trigger.capture(capture)
+}
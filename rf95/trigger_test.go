@@ -0,0 +1,44 @@
+package rf95
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrigger(t *testing.T) {
+	captures := make(chan []RxMessage, 1)
+	trigger, err := NewTrigger(func(rx RxMessage) bool { return rx.Payload[0] == 0xFF }, 2, 50*time.Millisecond,
+		func(capture []RxMessage) { captures <- capture })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range []byte{1, 2, 3, 0xFF, 4, 0xFF, 5} {
+		trigger.HandleRx(RxMessage{Payload: []byte{b}})
+	}
+
+	capture := <-captures
+	expected := []byte{2, 3, 0xFF, 4, 0xFF, 5}
+	if len(capture) != len(expected) {
+		t.Fatalf("captured %d messages, expected %d", len(capture), len(expected))
+	}
+	for i, b := range expected {
+		if capture[i].Payload[0] != b {
+			t.Fatalf("captured message %d is %x, expected %x", i, capture[i].Payload[0], b)
+		}
+	}
+
+	// After the window, messages go into the ring again.
+	trigger.HandleRx(RxMessage{Payload: []byte{6}})
+	trigger.HandleRx(RxMessage{Payload: []byte{0xFF}})
+
+	if capture := <-captures; len(capture) != 2 || capture[0].Payload[0] != 6 {
+		t.Fatalf("second capture is %v", capture)
+	}
+}
+
+func TestTriggerNegativePre(t *testing.T) {
+	if _, err := NewTrigger(func(RxMessage) bool { return true }, -1, time.Second, func([]RxMessage) {}); err == nil {
+		t.Fatal("creating a Trigger with a negative pre-trigger size succeeded")
+	}
+}
@@ -0,0 +1,63 @@
+package rf95
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// minTxPower is the lower bound of the SX1276's transmit power in dBm on its PA_BOOST pin.
+	minTxPower = 2
+
+	// maxTxPower is the upper bound of the SX1276's transmit power in dBm on its PA_BOOST pin.
+	maxTxPower = 20
+)
+
+// checkTxPower verifies that the transmit power in dBm is within the amplifier's range.
+func checkTxPower(dbm int) error {
+	if dbm < minTxPower || dbm > maxTxPower {
+		return fmt.Errorf("transmit power %d dBm is not in [%d, %d]", dbm, minTxPower, maxTxPower)
+	}
+	return nil
+}
+
+// TxPower sets the transmit power in dBm, e.g., to stay within the legal
+// limit for the antenna's gain.
+//
+// The firmware must support AT+TXP, as checked by Supports. The transmit power
+// read back from the rf95modem must match the requested one. The current
+// transmit power is reported as the Status' TxPower and restored after a
+// reconnect or Reset.
+//
+// This fails with ErrConfigLeased while a ConfigLease is held.
+func (modem *Modem) TxPower(dbm int) error {
+	return modem.withLease(nil, func() error { return modem.txPower(dbm) })
+}
+
+// txPower implements TxPower without respecting a ConfigLease.
+func (modem *Modem) txPower(dbm int) error {
+	if err := checkTxPower(dbm); err != nil {
+		return err
+	}
+
+	if supported, err := modem.Supports("AT+TXP"); err != nil {
+		return err
+	} else if !supported {
+		return fmt.Errorf("firmware does not support AT+TXP")
+	}
+
+	cmd := fmt.Sprintf("AT+TXP=%d", dbm)
+	results, cmdErr := modem.execute(cmd)
+	if cmdErr != nil {
+		return cmdErr
+	}
+
+	if readback, err := strconv.Atoi(results[0].match[1]); err != nil {
+		return modem.responseError(cmd, results[0].lines, err)
+	} else if readback != dbm {
+		return modem.responseError(cmd, results[0].lines, fmt.Errorf("transmit power is %d dBm, expected %d", readback, dbm))
+	}
+
+	// Refresh the known State, such that a reconnect restores the transmit power.
+	return modem.refreshMtu()
+}
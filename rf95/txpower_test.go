@@ -0,0 +1,134 @@
+package rf95
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModemTxPower(t *testing.T) {
+	var cmds []string
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		switch {
+		case cmd == "AT+HELP":
+			help := append([]string{}, testHelp[:len(testHelp)-1]...)
+			return append(help, "AT+TXP=<dbm>        Set the transmit power.\n", "+OK\n")
+		case cmd == "AT+TXP=17":
+			// The firmware clamps the transmit power.
+			return []string{"+TXP: 15\n"}
+		case strings.HasPrefix(cmd, "AT+TXP="):
+			return []string{"+TXP: " + strings.TrimPrefix(cmd, "AT+TXP=") + "\n"}
+		case cmd == "AT+INFO":
+			info := append([]string{}, testInfo[:len(testInfo)-1]...)
+			return append(info, "tx power:      14\n", "+OK\n")
+		default:
+			return nil
+		}
+	})
+
+	if err := modem.TxPower(14); err != nil {
+		t.Fatal(err)
+	}
+
+	var respErr *ResponseError
+	if err := modem.TxPower(17); !errors.As(err, &respErr) {
+		t.Fatalf("a mismatching readback returned %v, expected a ResponseError", err)
+	}
+
+	for _, dbm := range []int{1, 21} {
+		if err := modem.TxPower(dbm); err == nil {
+			t.Fatalf("transmit power %d dBm did not error", dbm)
+		}
+	}
+
+	if status, err := modem.FetchStatus(); err != nil {
+		t.Fatal(err)
+	} else if status.TxPower != 14 {
+		t.Fatalf("status has transmit power %d dBm, expected 14", status.TxPower)
+	}
+
+	if expected := "AT+HELP,AT+TXP=14,AT+INFO,AT+TXP=17,AT+INFO"; strings.Join(cmds, ",") != expected {
+		t.Fatalf("commands are %v, expected %s", cmds, expected)
+	}
+}
+
+func TestModemTxPowerUnsupported(t *testing.T) {
+	modem, _ := newTestModem(t, func(cmd string) []string {
+		if cmd == "AT+HELP" {
+			return testHelp
+		}
+		return nil
+	})
+
+	if err := modem.TxPower(14); err == nil {
+		t.Fatalf("setting the transmit power without firmware support did not error")
+	}
+}
+
+func TestModemTxPowerRestore(t *testing.T) {
+	var cmds []string
+	power := "14"
+	dev := &testControlDevice{testDevice: &testDevice{respond: func(cmd string) []string {
+		cmds = append(cmds, cmd)
+		switch {
+		case cmd == "AT+HELP":
+			help := append([]string{}, testHelp[:len(testHelp)-1]...)
+			return append(help, "AT+TXP=<dbm>        Set the transmit power.\n", "+OK\n")
+		case strings.HasPrefix(cmd, "AT+TXP="):
+			power = strings.TrimPrefix(cmd, "AT+TXP=")
+			return []string{"+TXP: " + power + "\n"}
+		case strings.HasPrefix(cmd, "AT+FREQ="):
+			return []string{"+FREQ: 868.10\n"}
+		case strings.HasPrefix(cmd, "AT+MODE="):
+			return []string{"+OK\n"}
+		case cmd == "AT+INFO":
+			info := append([]string{}, testInfo[:len(testInfo)-1]...)
+			return append(info, "tx power:      "+power+"\n", "+OK\n")
+		default:
+			return []string{"+FAIL\n"}
+		}
+	}}}
+	dev.pipeReader, dev.pipeWriter = io.Pipe()
+
+	modem, err := OpenTransport(NewTransport(func(context.Context) (io.ReadWriteCloser, error) {
+		return dev, nil
+	}), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modem.Close()
+
+	if err := modem.TxPower(10); err != nil {
+		t.Fatal(err)
+	}
+
+	// The board boots with the firmware's default transmit power.
+	cmds, power = nil, "14"
+	go func() {
+		time.Sleep(2 * resetPulse)
+		dev.inject("rf95modem firmware\n")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := modem.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "AT+FREQ=868.10,AT+MODE=0,AT+INFO,AT+HELP,AT+TXP=10,AT+INFO"; strings.Join(cmds, ",") != expected {
+		t.Fatalf("reset restored by %v, expected %s", cmds, expected)
+	}
+
+	var state bytes.Buffer
+	if err := modem.ExportState(&state); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(state.String(), `"tx_power": 10`) {
+		t.Fatalf("exported state %s lacks the restored transmit power", state.String())
+	}
+}
@@ -0,0 +1,126 @@
+package rf95
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WatchdogEvent records a recovery by an RxWatchdog.
+type WatchdogEvent struct {
+	// Time of the recovery.
+	Time time.Time
+
+	// Silence is the time since the last expected RxMessage.
+	Silence time.Duration
+
+	// Err of the recovery, nil on success.
+	Err error
+}
+
+// RxWatchdog recovers a receiver which stopped delivering RxMessages, as
+// observed with some firmware builds after hours of uptime.
+//
+// If no expected RxMessage, e.g., a peer's beacon, was received for the
+// Timeout, the receiver is recovered. Afterwards, the Timeout starts again.
+// The exported fields must be set before calling Watch.
+type RxWatchdog struct {
+	// Timeout without an expected RxMessage until a recovery.
+	Timeout time.Duration
+
+	// Recover the receiver, e.g., by resetting the rf95modem. If nil, the RX
+	// listener is switched off and on again by SetReceive.
+	Recover func(*Modem) error
+
+	// OnRecover is called, if not nil, after each recovery, e.g., to record it.
+	OnRecover func(WatchdogEvent)
+
+	modem  *Modem
+	expect func(RxMessage) bool
+	last   time.Time
+	mutex  sync.Mutex
+}
+
+// NewRxWatchdog for the Modem, recovering its receiver after the timeout without an expected RxMessage.
+//
+// The expect function filters the RxMessages proving a working receiver,
+// e.g., beacons of known peers. If nil, each RxMessage counts. This function
+// registers itself with its handler functions at the Modem. The timeout must
+// be positive.
+func NewRxWatchdog(modem *Modem, timeout time.Duration, expect func(RxMessage) bool) (*RxWatchdog, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("watchdog timeout %v must be positive", timeout)
+	}
+
+	watchdog := &RxWatchdog{
+		Timeout: timeout,
+		modem:   modem,
+		expect:  expect,
+		last:    time.Now(),
+	}
+
+	if _, err := modem.RegisterHandlers(watchdog.handleRx, nil); err != nil {
+		return nil, err
+	}
+	return watchdog, nil
+}
+
+// handleRx resets the Timeout for expected RxMessages.
+func (watchdog *RxWatchdog) handleRx(rx RxMessage) {
+	if watchdog.expect != nil && !watchdog.expect(rx) {
+		return
+	}
+
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+
+	watchdog.last = time.Now()
+}
+
+// revive the receiver after the silence and report the WatchdogEvent.
+func (watchdog *RxWatchdog) revive(silence time.Duration) {
+	var err error
+	if watchdog.Recover != nil {
+		err = watchdog.Recover(watchdog.modem)
+	} else if err = watchdog.modem.SetReceive(false); err == nil {
+		err = watchdog.modem.SetReceive(true)
+	}
+
+	if watchdog.OnRecover != nil {
+		watchdog.OnRecover(WatchdogEvent{Time: time.Now(), Silence: silence, Err: err})
+	}
+}
+
+// Watch the receiver until the Context is done or the Modem is finished.
+func (watchdog *RxWatchdog) Watch(ctx context.Context) {
+	interval := watchdog.Timeout / 4
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-watchdog.modem.ctx.Done():
+			return
+
+		case <-ticker.C:
+			watchdog.mutex.Lock()
+			silence := time.Since(watchdog.last)
+			if silence >= watchdog.Timeout {
+				watchdog.last = time.Now()
+			}
+			watchdog.mutex.Unlock()
+
+			if silence >= watchdog.Timeout {
+				watchdog.revive(silence)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package rf95
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRxWatchdog(t *testing.T) {
+	var cmds []string
+	var cmdsMutex sync.Mutex
+	modem, dev := newTestModem(t, func(cmd string) []string {
+		if strings.HasPrefix(cmd, "AT+RX=") {
+			cmdsMutex.Lock()
+			cmds = append(cmds, cmd)
+			cmdsMutex.Unlock()
+			return []string{"+OK\n"}
+		}
+		return nil
+	})
+
+	if _, err := NewRxWatchdog(modem, 0, nil); err == nil {
+		t.Fatal("creating a watchdog without a timeout did not error")
+	}
+
+	watchdog, err := NewRxWatchdog(modem, 100*time.Millisecond, func(rx RxMessage) bool { return string(rx.Payload) == "AAA" })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan WatchdogEvent, 1)
+	watchdog.OnRecover = func(event WatchdogEvent) { events <- event }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchdog.Watch(ctx)
+
+	// Expected beacons keep the watchdog calm, while others do not.
+	for i := 0; i < 6; i++ {
+		dev.inject("+RX 3,414141,-15,8\n")
+		time.Sleep(25 * time.Millisecond)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("watchdog recovered at %v despite beacons", event.Time)
+	default:
+	}
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		dev.inject("+RX 3,424242,-15,8\n")
+		time.Sleep(25 * time.Millisecond)
+	}
+
+	event := <-events
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	} else if event.Silence < 100*time.Millisecond || event.Time.Before(start) {
+		t.Fatalf("watchdog recovered after %v of silence, expected at least 100ms", event.Silence)
+	}
+
+	cmdsMutex.Lock()
+	defer cmdsMutex.Unlock()
+	if expected := []string{"AT+RX=0", "AT+RX=1"}; !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("watchdog sent %v, expected %v", cmds, expected)
+	}
+}